@@ -0,0 +1,15 @@
+// Get all active access keys
+data "infra_access_keys" "all" {}
+
+output "my_access_keys" {
+  value = data.infra_access_keys.all.access_keys
+}
+
+// Get all access keys, including expired keys, issued for the connector
+data "infra_access_keys" "connector" {
+  filter {
+    user_name = "connector"
+  }
+
+  show_expired = true
+}
@@ -0,0 +1,21 @@
+// Get a destination by name
+data "infra_destination" "example" {
+  name = "my_cluster"
+}
+
+output "my_cluster_namespaces" {
+  value = data.infra_destination.example.resources
+}
+
+// Grant a user edit to every namespace reported by the connector
+resource "infra_grant" "example" {
+  for_each = toset(data.infra_destination.example.resources)
+
+  user_name = "example@example.com"
+
+  kubernetes {
+    cluster   = data.infra_destination.example.name
+    namespace = each.value
+    role      = "edit"
+  }
+}
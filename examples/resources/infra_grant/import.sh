@@ -0,0 +1 @@
+terraform import infra_grant.example <grant_id>
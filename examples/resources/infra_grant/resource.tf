@@ -35,3 +35,35 @@ resource "infra_grant" "kubernetes_namespace_edit" {
     namespace = "default"
   }
 }
+
+# Grant a user, by name, access to an SSH host
+resource "infra_grant" "ssh_connect" {
+  user_name = "example@example.com"
+
+  ssh {
+    host = "my_host"
+  }
+}
+
+# Grant a group, by ID, a role on any kind of destination
+resource "infra_grant" "destination_view" {
+  group_id = infra_group.example.id
+
+  destination {
+    name     = "my_cluster"
+    resource = "default"
+    role     = "view"
+  }
+}
+
+# Grant a user, by name, temporary Kubernetes cluster admin. The grant is removed by the
+# next apply after it expires.
+resource "infra_grant" "kubernetes_cluster_admin_temporary" {
+  user_name  = "example@example.com"
+  expires_in = "8h"
+
+  kubernetes {
+    cluster = "my_cluster"
+    role    = "cluster-admin"
+  }
+}
@@ -0,0 +1,27 @@
+data "infra_users" "developers" {
+  filter {
+    group_name = "Developers"
+  }
+}
+
+resource "infra_group" "operators" {
+  name = "Operators"
+}
+
+# Grant every developer and the operators group view to the cluster
+# and edit to the default namespace
+resource "infra_grants" "example" {
+  user_ids  = data.infra_users.developers.users[*].id
+  group_ids = [infra_group.operators.id]
+
+  kubernetes {
+    cluster = "my_cluster"
+    role    = "view"
+  }
+
+  kubernetes {
+    cluster   = "my_cluster"
+    namespace = "default"
+    role      = "edit"
+  }
+}
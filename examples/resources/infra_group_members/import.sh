@@ -0,0 +1 @@
+terraform import infra_group_members.example <group_id>
@@ -0,0 +1,21 @@
+resource "infra_user" "alice" {
+  name = "alice@example.com"
+}
+
+resource "infra_user" "bob" {
+  name = "bob@example.com"
+}
+
+resource "infra_group" "example" {
+  name = "Example"
+}
+
+# Set the members of a group. Any other members will be removed.
+resource "infra_group_members" "example" {
+  group_id = infra_group.example.id
+
+  user_ids = [
+    infra_user.alice.id,
+    infra_user.bob.id,
+  ]
+}
@@ -1,4 +1,4 @@
-# Connect a generic OIDC identity provider
+# Connect a generic OIDC identity provider, such as Keycloak or Auth0
 resource "infra_identity_provider" "example" {
   issuer        = "https://my.oidc.provider.com/"
   client_id     = "example_client_id"
@@ -0,0 +1 @@
+terraform import infra_resource_grants.example <cluster>.<namespace>
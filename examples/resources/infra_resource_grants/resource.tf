@@ -0,0 +1,23 @@
+resource "infra_user" "alice" {
+  name = "alice@example.com"
+}
+
+resource "infra_group" "developers" {
+  name = "Developers"
+}
+
+# Set the grants for a Kubernetes namespace. Any other grants on the namespace will be removed.
+resource "infra_resource_grants" "example" {
+  cluster   = "example-cluster"
+  namespace = "default"
+
+  grant {
+    user_id = infra_user.alice.id
+    role    = "admin"
+  }
+
+  grant {
+    group_id = infra_group.developers.id
+    role     = "edit"
+  }
+}
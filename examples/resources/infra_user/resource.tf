@@ -2,3 +2,32 @@
 resource "infra_user" "example" {
   name = "example@example.com"
 }
+
+variable "one_time_password" {
+  type      = string
+  sensitive = true
+}
+
+# Create a user with a one-time password which is not stored in the state. Change
+# `password_wo_version` to send a new value of `password_wo` to Infra.
+resource "infra_user" "write_only" {
+  name                = "write-only@example.com"
+  password_wo         = var.one_time_password
+  password_wo_version = "1"
+}
+
+# Create a user and reset their password whenever `rotation` changes.
+resource "infra_user" "rotated" {
+  name = "rotated@example.com"
+
+  reset_password = {
+    rotation = "2023-01"
+  }
+}
+
+# Create a user which is kept in Infra, without any grants or group memberships, when
+# this resource is destroyed.
+resource "infra_user" "offboarded" {
+  name            = "offboarded@example.com"
+  deletion_policy = "remove_grants_only"
+}
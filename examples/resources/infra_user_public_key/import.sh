@@ -0,0 +1 @@
+terraform import infra_user_public_key.example <user_id>/<public_key_id>
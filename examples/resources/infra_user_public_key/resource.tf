@@ -0,0 +1,6 @@
+# Add an SSH public key for the user of the provider's access key
+resource "infra_user_public_key" "example" {
+  user_name  = "example@example.com"
+  name       = "laptop"
+  public_key = file("~/.ssh/id_ed25519.pub")
+}
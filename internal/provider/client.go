@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+
+	"github.com/infrahq/infra/api"
+)
+
+// infraAPIVersion is the API version sent with requests which are not yet supported by
+// api.Client. It must match the version used by api.Client.
+const infraAPIVersion = "0.18.1"
+
+// updateGrants adds and removes grants in a single request.
+func updateGrants(ctx context.Context, client *api.Client, request *api.UpdateGrantsRequest) error {
+	return do(ctx, client, http.MethodPatch, "/api/grants", request)
+}
+
+func do(ctx context.Context, client *api.Client, method, path string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", client.URL, path), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+client.AccessKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Infra-Version", infraAPIVersion)
+	req.Header.Set("User-Agent", fmt.Sprintf("Infra/%v (%s %v; %v/%v)", infraAPIVersion, client.Name, client.Version, runtime.GOOS, runtime.GOARCH))
+
+	for k, v := range client.Headers {
+		req.Header[k] = v
+	}
+
+	resp, err := client.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %q: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiError := api.Error{Code: int32(resp.StatusCode)}
+		if err := json.Unmarshal(data, &apiError); err != nil {
+			apiError.Message = string(data)
+		}
+
+		return apiError
+	}
+
+	return nil
+}
+
+// listAll calls list with each page number until the last page and returns the items from
+// every page.
+func listAll[T any](list func(page int) (*api.ListResponse[T], error)) ([]T, error) {
+	var items []T
+
+	for page := 1; ; page++ {
+		response, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, response.Items...)
+
+		if response.Page >= response.TotalPages {
+			return items, nil
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
+)
+
+func TestListAll(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c"}}
+
+	var requested []int
+	items, err := listAll(func(page int) (*api.ListResponse[string], error) {
+		requested = append(requested, page)
+
+		return &api.ListResponse[string]{
+			Items:              pages[page-1],
+			PaginationResponse: api.PaginationResponse{Page: page, TotalPages: len(pages)},
+		}, nil
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, []string{"a", "b", "c"})
+	assert.DeepEqual(t, requested, []int{1, 2})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := listAll(func(page int) (*api.ListResponse[string], error) {
+			return nil, errors.New("failed")
+		})
+		assert.Error(t, err, "failed")
+	})
+}
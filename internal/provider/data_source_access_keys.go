@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/infrahq/infra/api"
+)
+
+type accessKeysDataSource struct {
+	frameworkDataSource
+}
+
+type accessKeysDataSourceModel struct {
+	ID          types.String                         `tfsdk:"id"`
+	Filter      []accessKeysFilterModel              `tfsdk:"filter"`
+	ShowExpired types.Bool                           `tfsdk:"show_expired"`
+	AccessKeys  []accessKeysDataSourceAccessKeyModel `tfsdk:"access_keys"`
+}
+
+type accessKeysFilterModel struct {
+	Name     types.String `tfsdk:"name"`
+	UserID   types.String `tfsdk:"user_id"`
+	UserName types.String `tfsdk:"user_name"`
+}
+
+type accessKeysDataSourceAccessKeyModel struct {
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	IssuedForID       types.String `tfsdk:"issued_for_id"`
+	IssuedForName     types.String `tfsdk:"issued_for_name"`
+	Created           types.String `tfsdk:"created"`
+	ExpiresAt         types.String `tfsdk:"expires_at"`
+	LastUsed          types.String `tfsdk:"last_used"`
+	InactivityTimeout types.String `tfsdk:"inactivity_timeout"`
+}
+
+func dataSourceAccessKeys() datasource.DataSource {
+	return &accessKeysDataSource{}
+}
+
+func (d *accessKeysDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_keys"
+}
+
+func (d *accessKeysDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Get a list of Infra access keys.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"show_expired": schema.BoolAttribute{
+				MarkdownDescription: "Include expired access keys. Default is `false`.",
+				Optional:            true,
+			},
+			"access_keys": schema.ListAttribute{
+				MarkdownDescription: "The access keys matching the filter.",
+				Computed:            true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"id":                 types.StringType,
+						"name":               types.StringType,
+						"issued_for_id":      types.StringType,
+						"issued_for_name":    types.StringType,
+						"created":            types.StringType,
+						"expires_at":         types.StringType,
+						"last_used":          types.StringType,
+						"inactivity_timeout": types.StringType,
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"filter": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the access key.",
+							Optional:            true,
+						},
+						"user_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the user the access key was issued for. Cannot be used with `user_name`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringIsID(),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("user_name")),
+							},
+						},
+						"user_name": schema.StringAttribute{
+							MarkdownDescription: "The name of the user the access key was issued for. Cannot be used with `user_id`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("user_id")),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *accessKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	client := d.meta.client
+
+	var data accessKeysDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	request := api.ListAccessKeysRequest{
+		ShowExpired: data.ShowExpired.ValueBool(),
+		PaginationRequest: api.PaginationRequest{
+			Limit: 1000,
+		},
+	}
+
+	for _, filter := range data.Filter {
+		request.Name = filter.Name.ValueString()
+
+		var user *api.User
+		var err error
+
+		switch {
+		case filter.UserID.ValueString() != "":
+			user, err = userFromID(ctx, client, filter.UserID.ValueString())
+		case filter.UserName.ValueString() != "":
+			user, err = userFromEmail(ctx, client, filter.UserName.ValueString())
+		}
+
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+			return
+		}
+
+		if user != nil {
+			request.UserID = user.ID
+		}
+	}
+
+	accessKeys, err := listAll(func(page int) (*api.ListResponse[api.AccessKey], error) {
+		request.Page = page
+		return client.ListAccessKeys(ctx, request)
+	})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+		return
+	}
+
+	sha1sum := sha1.New()
+
+	data.AccessKeys = make([]accessKeysDataSourceAccessKeyModel, 0, len(accessKeys))
+	for _, item := range accessKeys {
+		accessKey := accessKeysDataSourceAccessKeyModel{
+			ID:                types.StringValue(item.ID.String()),
+			Name:              types.StringValue(item.Name),
+			IssuedForID:       types.StringValue(item.IssuedFor.String()),
+			IssuedForName:     types.StringValue(item.IssuedForName),
+			Created:           types.StringValue(FormatTime(item.Created)),
+			ExpiresAt:         types.StringValue(FormatTime(item.Expires)),
+			LastUsed:          types.StringValue(FormatTime(item.LastUsed)),
+			InactivityTimeout: types.StringValue(FormatTime(item.InactivityTimeout)),
+		}
+
+		io.WriteString(sha1sum, item.ID.String())
+
+		data.AccessKeys = append(data.AccessKeys, accessKey)
+	}
+
+	data.ID = types.StringValue(hex.EncodeToString(sha1sum.Sum(nil)))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceAccessKeys(t *testing.T) {
+	name := randomName()
+
+	dataSourceName := fmt.Sprintf("data.infra_access_keys.%s", t.Name())
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAccessKey_connectorWithName(t, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fmt.Sprintf("infra_access_key.%s", t.Name()), "name", name),
+				),
+			},
+			{
+				Config: composeTestConfigFunc(
+					testAccResourceAccessKey_connectorWithName(t, name),
+					testAccDataSourceAccessKeys_filterByName(t, name),
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "access_keys.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "access_keys.0.name", name),
+					resource.TestCheckResourceAttr(dataSourceName, "access_keys.0.issued_for_name", "connector"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "access_keys.0.id", fmt.Sprintf("infra_access_key.%s", t.Name()), "id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "access_keys.0.expires_at", fmt.Sprintf("infra_access_key.%s", t.Name()), "expires_at"),
+				),
+			},
+			{
+				Config: composeTestConfigFunc(
+					testAccResourceAccessKey_connectorWithName(t, name),
+					testAccDataSourceAccessKeys_filterByUserName(t, "connector"),
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "access_keys.*", map[string]string{
+						"name":            name,
+						"issued_for_name": "connector",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceAccessKey_connectorWithName(t *testing.T, name string) string {
+	return fmt.Sprintf(`
+resource "infra_access_key" "%[1]s" {
+	name = "%[2]s"
+}`, t.Name(), name)
+}
+
+func testAccDataSourceAccessKeys_filterByName(t *testing.T, name string) string {
+	return fmt.Sprintf(`
+data "infra_access_keys" "%[1]s" {
+	filter {
+		name = "%[2]s"
+	}
+
+	depends_on = [
+		infra_access_key.%[1]s,
+	]
+}`, t.Name(), name)
+}
+
+func testAccDataSourceAccessKeys_filterByUserName(t *testing.T, name string) string {
+	return fmt.Sprintf(`
+data "infra_access_keys" "%[1]s" {
+	filter {
+		user_name = "%[2]s"
+	}
+
+	depends_on = [
+		infra_access_key.%[1]s,
+	]
+}`, t.Name(), name)
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+type destinationDataSource struct {
+	frameworkDataSource
+}
+
+type destinationDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Kind      types.String `tfsdk:"kind"`
+	URL       types.String `tfsdk:"url"`
+	CA        types.String `tfsdk:"ca"`
+	Version   types.String `tfsdk:"version"`
+	Connected types.Bool   `tfsdk:"connected"`
+	LastSeen  types.String `tfsdk:"last_seen"`
+	Resources []string     `tfsdk:"resources"`
+	Roles     []string     `tfsdk:"roles"`
+}
+
+func dataSourceDestination() datasource.DataSource {
+	return &destinationDataSource{}
+}
+
+func (d *destinationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_destination"
+}
+
+func (d *destinationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Get an Infra destination and the connection details reported by its connector.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the destination. One of `id`, `name` must be set.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringIsID(),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the destination. One of `id`, `name` must be set.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"kind": schema.StringAttribute{
+				MarkdownDescription: "The kind of the destination, e.g. `kubernetes` or `ssh`.",
+				Computed:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The host and port used to connect to the destination.",
+				Computed:            true,
+			},
+			"ca": schema.StringAttribute{
+				MarkdownDescription: "The destination's PEM-encoded certificate authority.",
+				Computed:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "The version of the connector for this destination.",
+				Computed:            true,
+			},
+			"connected": schema.BoolAttribute{
+				MarkdownDescription: "Whether the destination's connector is currently connected.",
+				Computed:            true,
+			},
+			"last_seen": schema.StringAttribute{
+				MarkdownDescription: "The date-time when the destination's connector was last seen.",
+				Computed:            true,
+			},
+			"resources": schema.ListAttribute{
+				MarkdownDescription: "The resources reported by the connector. For Kubernetes destinations, this is the list of namespaces.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"roles": schema.ListAttribute{
+				MarkdownDescription: "The roles reported by the connector. For Kubernetes destinations, this is the list of cluster roles.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *destinationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	client := d.meta.client
+
+	var data destinationDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var destination *api.Destination
+	var err error
+
+	switch {
+	case data.ID.ValueString() != "":
+		var id uid.ID
+		id, err = uid.Parse([]byte(data.ID.ValueString()))
+		if err == nil {
+			destination, err = destinationFromID(ctx, client, id)
+		}
+	case data.Name.ValueString() != "":
+		destination, err = destinationFromName(ctx, client, data.Name.ValueString())
+	default:
+		err = fmt.Errorf("one of `id,name` must be specified")
+	}
+
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+		return
+	}
+
+	data.ID = types.StringValue(destination.ID.String())
+	data.Name = types.StringValue(destination.Name)
+	data.Kind = types.StringValue(destination.Kind)
+	data.URL = types.StringValue(destination.Connection.URL)
+	data.CA = types.StringValue(string(destination.Connection.CA))
+	data.Version = types.StringValue(destination.Version)
+	data.Connected = types.BoolValue(destination.Connected)
+	data.LastSeen = types.StringValue(FormatTime(destination.LastSeen))
+	data.Resources = append([]string{}, destination.Resources...)
+	data.Roles = append([]string{}, destination.Roles...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func destinationFromName(ctx context.Context, client *api.Client, name string) (*api.Destination, error) {
+	request := api.ListDestinationsRequest{
+		Name: name,
+		PaginationRequest: api.PaginationRequest{
+			Limit: 1,
+		},
+	}
+
+	response, err := client.ListDestinations(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Count < 1 {
+		return nil, fmt.Errorf("destination not found: %s", name)
+	}
+
+	return &response.Items[0], nil
+}
+
+// destinationFromID finds a destination by ID. The API does not provide a way to get a
+// single destination so list all destinations and search for a match.
+func destinationFromID(ctx context.Context, client *api.Client, id uid.ID) (*api.Destination, error) {
+	destinations, err := listAll(func(page int) (*api.ListResponse[api.Destination], error) {
+		return client.ListDestinations(ctx, api.ListDestinationsRequest{
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range destinations {
+		if destinations[i].ID == id {
+			return &destinations[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("destination not found: %s", id)
+}
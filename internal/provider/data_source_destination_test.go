@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+func TestDataSourceDestinationRead(t *testing.T) {
+	lastSeen := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	other := api.Destination{ID: uid.New(), Name: "staging", Kind: "kubernetes"}
+	destination := api.Destination{
+		ID:   uid.New(),
+		Name: "production",
+		Kind: "kubernetes",
+		Connection: api.DestinationConnection{
+			URL: "production.example.com:443",
+			CA:  "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n",
+		},
+		Resources: []string{"default", "kube-system"},
+		Roles:     []string{"cluster-admin", "view"},
+		LastSeen:  api.Time(lastSeen),
+		Connected: true,
+		Version:   "0.20.0",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/destinations", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") == destination.Name {
+			testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Destination]{Count: 1, Items: []api.Destination{destination}})
+			return
+		}
+
+		// serve one destination per page to check that lookup by ID reads every page
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		items := []api.Destination{other}
+		if page == 2 {
+			items = []api.Destination{destination}
+		}
+
+		testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Destination]{
+			Count:              1,
+			Items:              items,
+			PaginationResponse: api.PaginationResponse{Page: page, TotalPages: 2},
+		})
+	})
+
+	meta := testProviderMeta(t, mux)
+
+	expected := destinationDataSourceModel{
+		ID:        types.StringValue(destination.ID.String()),
+		Name:      types.StringValue("production"),
+		Kind:      types.StringValue("kubernetes"),
+		URL:       types.StringValue("production.example.com:443"),
+		CA:        types.StringValue("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n"),
+		Version:   types.StringValue("0.20.0"),
+		Connected: types.BoolValue(true),
+		LastSeen:  types.StringValue("2023-01-02T03:04:05Z"),
+		Resources: []string{"default", "kube-system"},
+		Roles:     []string{"cluster-admin", "view"},
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		actual := testReadDestination(t, meta, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "production"),
+		})
+		assert.DeepEqual(t, actual, expected)
+	})
+
+	t.Run("by id", func(t *testing.T) {
+		actual := testReadDestination(t, meta, map[string]tftypes.Value{
+			"id": tftypes.NewValue(tftypes.String, destination.ID.String()),
+		})
+		assert.DeepEqual(t, actual, expected)
+	})
+}
+
+// testReadDestination reads the infra_destination data source with the config values. Each
+// attribute which is not set in values is null.
+func testReadDestination(t *testing.T, meta *providerMeta, values map[string]tftypes.Value) destinationDataSourceModel {
+	t.Helper()
+
+	ctx := context.Background()
+
+	d := &destinationDataSource{frameworkDataSource{meta: meta}}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if v, ok := values[name]; ok {
+			attributes[name] = v
+		}
+	}
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+
+	d.Read(ctx, req, &resp)
+	assert.Assert(t, !resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	var data destinationDataSourceModel
+	assert.Assert(t, !resp.State.Get(ctx, &data).HasError())
+
+	return data
+}
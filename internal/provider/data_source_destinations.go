@@ -4,60 +4,82 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
-	"fmt"
 	"io"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/infrahq/infra/api"
 )
 
-func dataSourceDestinations() *schema.Resource {
-	return &schema.Resource{
-		Description: "Get a list of Infra destinations.",
-
-		ReadContext: dataSourceDestinationsRead,
-
-		Schema: map[string]*schema.Schema{
-			"filter": &schema.Schema{
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": &schema.Schema{
-							Description: "The name of the destination.",
-							Type:        schema.TypeString,
-							Optional:    true,
-						},
-						"kind": &schema.Schema{
-							Description: "The kind of the destination.",
-							Type:        schema.TypeString,
-							Optional:    true,
-						},
+type destinationsDataSource struct {
+	frameworkDataSource
+}
+
+type destinationsDataSourceModel struct {
+	ID           types.String                             `tfsdk:"id"`
+	Filter       []destinationsFilterModel                `tfsdk:"filter"`
+	Destinations []destinationsDataSourceDestinationModel `tfsdk:"destinations"`
+}
+
+type destinationsFilterModel struct {
+	Name types.String `tfsdk:"name"`
+	Kind types.String `tfsdk:"kind"`
+}
+
+type destinationsDataSourceDestinationModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Kind types.String `tfsdk:"kind"`
+}
+
+func dataSourceDestinations() datasource.DataSource {
+	return &destinationsDataSource{}
+}
+
+func (d *destinationsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_destinations"
+}
+
+func (d *destinationsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Get a list of Infra destinations.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"destinations": schema.ListAttribute{
+				MarkdownDescription: "The destinations matching the filter.",
+				Computed:            true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"id":   types.StringType,
+						"name": types.StringType,
+						"kind": types.StringType,
 					},
 				},
 			},
-			"destinations": &schema.Schema{
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": &schema.Schema{
-							Description: "The ID of the destination.",
-							Type:        schema.TypeString,
-							Computed:    true,
-						},
-						"name": &schema.Schema{
-							Description: "The name of the destination.",
-							Type:        schema.TypeString,
-							Computed:    true,
+		},
+
+		Blocks: map[string]schema.Block{
+			"filter": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the destination.",
+							Optional:            true,
 						},
-						"kind": &schema.Schema{
-							Description: "The kind of the destination.",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"kind": schema.StringAttribute{
+							MarkdownDescription: "The kind of the destination.",
+							Optional:            true,
 						},
 					},
 				},
@@ -66,8 +88,15 @@ func dataSourceDestinations() *schema.Resource {
 	}
 }
 
-func dataSourceDestinationsRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+func (d *destinationsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	client := d.meta.client
+
+	var data destinationsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	request := api.ListDestinationsRequest{
 		PaginationRequest: api.PaginationRequest{
@@ -75,36 +104,33 @@ func dataSourceDestinationsRead(ctx context.Context, d *schema.ResourceData, m a
 		},
 	}
 
-	for i := range d.Get("filter").([]interface{}) {
-		request.Name = d.Get(fmt.Sprintf("filter.%d.name", i)).(string)
-		request.Kind = d.Get(fmt.Sprintf("filter.%d.kind", i)).(string)
+	for _, filter := range data.Filter {
+		request.Name = filter.Name.ValueString()
+		request.Kind = filter.Kind.ValueString()
 	}
 
 	response, err := client.ListDestinations(ctx, request)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+		return
 	}
 
 	sha1sum := sha1.New()
 
-	destinations := make([]map[string]interface{}, 0, response.Count)
+	data.Destinations = make([]destinationsDataSourceDestinationModel, 0, response.Count)
 	for _, item := range response.Items {
-		destination := make(map[string]interface{})
-		destination["id"] = item.ID.String()
-		destination["name"] = item.Name
-		destination["kind"] = item.Kind
+		destination := destinationsDataSourceDestinationModel{
+			ID:   types.StringValue(item.ID.String()),
+			Name: types.StringValue(item.Name),
+			Kind: types.StringValue(item.Kind),
+		}
 
 		io.WriteString(sha1sum, item.ID.String())
 
-		destinations = append(destinations, destination)
-	}
-
-	if err := d.Set("destinations", destinations); err != nil {
-		return diag.FromErr(err)
+		data.Destinations = append(data.Destinations, destination)
 	}
 
-	d.SetId(hex.EncodeToString(sha1sum.Sum(nil)))
+	data.ID = types.StringValue(hex.EncodeToString(sha1sum.Sum(nil)))
 
-	var diags diag.Diagnostics
-	return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -4,79 +4,102 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
-	"fmt"
 	"io"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/infrahq/infra/api"
 )
 
-func dataSourceGroups() *schema.Resource {
-	return &schema.Resource{
-		Description: "Get a list of Infra groups.",
-
-		ReadContext: dataSourceGroupsRead,
-
-		Schema: map[string]*schema.Schema{
-			"filter": &schema.Schema{
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": &schema.Schema{
-							Description: "The name of the group.",
-							Type:        schema.TypeString,
-							Optional:    true,
-						},
-						"user_id": &schema.Schema{
-							Description:      "The ID of the user who belongs to this group.",
-							Type:             schema.TypeString,
-							Optional:         true,
-							ValidateDiagFunc: validateStringIsID(),
-							ConflictsWith: []string{
-								"filter.0.user_name",
-							},
-						},
-						"user_name": &schema.Schema{
-							Description:      "The name of the user who belongs to this group.",
-							Type:             schema.TypeString,
-							Optional:         true,
-							ValidateDiagFunc: validateStringIsEmail(),
-							ConflictsWith: []string{
-								"filter.0.user_id",
-							},
-						},
+type groupsDataSource struct {
+	frameworkDataSource
+}
+
+type groupsDataSourceModel struct {
+	ID           types.String                 `tfsdk:"id"`
+	Filter       []groupsFilterModel          `tfsdk:"filter"`
+	IncludeUsers types.Bool                   `tfsdk:"include_users"`
+	Groups       []groupsDataSourceGroupModel `tfsdk:"groups"`
+}
+
+type groupsFilterModel struct {
+	Name     types.String `tfsdk:"name"`
+	UserID   types.String `tfsdk:"user_id"`
+	UserName types.String `tfsdk:"user_name"`
+}
+
+type groupsDataSourceGroupModel struct {
+	ID    types.String `tfsdk:"id"`
+	Name  types.String `tfsdk:"name"`
+	Users []string     `tfsdk:"users"`
+}
+
+func dataSourceGroups() datasource.DataSource {
+	return &groupsDataSource{}
+}
+
+func (d *groupsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_groups"
+}
+
+func (d *groupsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Get a list of Infra groups.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"include_users": schema.BoolAttribute{
+				MarkdownDescription: "Include each group's members. Default is `false`.",
+				Optional:            true,
+			},
+			"groups": schema.ListAttribute{
+				MarkdownDescription: "The groups matching the filter.",
+				Computed:            true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"id":    types.StringType,
+						"name":  types.StringType,
+						"users": types.ListType{ElemType: types.StringType},
 					},
 				},
 			},
-			"include_users": &schema.Schema{
-				Description: "Include each group's members. Default is `false`.",
-				Type:        schema.TypeBool,
-				Optional:    true,
-			},
-			"groups": &schema.Schema{
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": &schema.Schema{
-							Description: "The ID of the group.",
-							Type:        schema.TypeString,
-							Computed:    true,
+		},
+
+		Blocks: map[string]schema.Block{
+			"filter": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the group.",
+							Optional:            true,
 						},
-						"name": &schema.Schema{
-							Description: "The name of the group.",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"user_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the user who belongs to this group. Cannot be used with `user_name`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringIsID(),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("user_name")),
+							},
 						},
-						"users": &schema.Schema{
-							Description: "The members of the group.",
-							Type:        schema.TypeList,
-							Computed:    true,
-							Elem:        schema.TypeString,
+						"user_name": schema.StringAttribute{
+							MarkdownDescription: "The name of the user who belongs to this group. Cannot be used with `user_id`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringIsEmail(),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("user_id")),
+							},
 						},
 					},
 				},
@@ -85,8 +108,15 @@ func dataSourceGroups() *schema.Resource {
 	}
 }
 
-func dataSourceGroupsRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+func (d *groupsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	client := d.meta.client
+
+	var data groupsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	request := api.ListGroupsRequest{
 		PaginationRequest: api.PaginationRequest{
@@ -94,35 +124,48 @@ func dataSourceGroupsRead(ctx context.Context, d *schema.ResourceData, m any) di
 		},
 	}
 
-	for i := range d.Get("filter").([]interface{}) {
-		request.Name = d.Get(fmt.Sprintf("filter.%d.name", i)).(string)
+	for _, filter := range data.Filter {
+		request.Name = filter.Name.ValueString()
 
-		if d.Get(fmt.Sprintf("filter.%d.user_id", i)).(string) != "" || d.Get(fmt.Sprintf("filter.%d.user_name", i)).(string) != "" {
-			user, err := userFromIDOrEmail(ctx, client, d, fmt.Sprintf("filter.%d.user_id", i), fmt.Sprintf("filter.%d.user_name", i))
-			if err != nil {
-				return diag.FromErr(err)
-			}
+		var user *api.User
+		var err error
+
+		switch {
+		case filter.UserID.ValueString() != "":
+			user, err = userFromID(ctx, client, filter.UserID.ValueString())
+		case filter.UserName.ValueString() != "":
+			user, err = userFromEmail(ctx, client, filter.UserName.ValueString())
+		}
+
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+			return
+		}
 
+		if user != nil {
 			request.UserID = user.ID
 		}
 	}
 
 	response, err := client.ListGroups(ctx, request)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+		return
 	}
 
 	sha1sum := sha1.New()
 
-	groups := make([]map[string]interface{}, 0, response.Count)
+	data.Groups = make([]groupsDataSourceGroupModel, 0, response.Count)
 	for _, item := range response.Items {
-		group := make(map[string]interface{})
-		group["id"] = item.ID.String()
-		group["name"] = item.Name
+		group := groupsDataSourceGroupModel{
+			ID:    types.StringValue(item.ID.String()),
+			Name:  types.StringValue(item.Name),
+			Users: []string{},
+		}
 
 		io.WriteString(sha1sum, item.ID.String())
 
-		if d.Get("include_users").(bool) {
+		if data.IncludeUsers.ValueBool() {
 			request := api.ListUsersRequest{
 				Group: item.ID,
 				PaginationRequest: api.PaginationRequest{
@@ -132,26 +175,19 @@ func dataSourceGroupsRead(ctx context.Context, d *schema.ResourceData, m any) di
 
 			response, err := client.ListUsers(ctx, request)
 			if err != nil {
-				return diag.FromErr(err)
+				resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+				return
 			}
 
-			users := make([]string, 0, response.Count)
 			for _, user := range response.Items {
-				users = append(users, user.Name)
+				group.Users = append(group.Users, user.Name)
 			}
-
-			group["users"] = users
 		}
 
-		groups = append(groups, group)
-	}
-
-	if err := d.Set("groups", groups); err != nil {
-		return diag.FromErr(err)
+		data.Groups = append(data.Groups, group)
 	}
 
-	d.SetId(hex.EncodeToString(sha1sum.Sum(nil)))
+	data.ID = types.StringValue(hex.EncodeToString(sha1sum.Sum(nil)))
 
-	var diags diag.Diagnostics
-	return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
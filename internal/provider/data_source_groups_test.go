@@ -14,8 +14,8 @@ func TestAccDataSourceGroups(t *testing.T) {
 	dataSourceName := fmt.Sprintf("data.infra_groups.%s", t.Name())
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: composeTestConfigFunc(
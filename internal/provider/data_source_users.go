@@ -4,79 +4,104 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
-	"fmt"
 	"io"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/infrahq/infra/api"
 )
 
-func dataSourceUsers() *schema.Resource {
-	return &schema.Resource{
-		Description: "Get a list of Infra users.",
-
-		ReadContext: dataSourceUsersRead,
-
-		Schema: map[string]*schema.Schema{
-			"filter": &schema.Schema{
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": &schema.Schema{
-							Description:      "The name of the user.",
-							Type:             schema.TypeString,
-							ValidateDiagFunc: validateStringIsEmail(),
-							Optional:         true,
-						},
-						"group_id": &schema.Schema{
-							Description:      "The ID of the group where user is a member.",
-							Type:             schema.TypeString,
-							Optional:         true,
-							ValidateDiagFunc: validateStringIsID(),
-							ConflictsWith: []string{
-								"filter.0.group_name",
-							},
-						},
-						"group_name": &schema.Schema{
-							Description: "The name of the group where user is a member.",
-							Type:        schema.TypeString,
-							Optional:    true,
-							ConflictsWith: []string{
-								"filter.0.group_id",
-							},
-						},
+type usersDataSource struct {
+	frameworkDataSource
+}
+
+type usersDataSourceModel struct {
+	ID            types.String               `tfsdk:"id"`
+	Filter        []usersFilterModel         `tfsdk:"filter"`
+	IncludeGroups types.Bool                 `tfsdk:"include_groups"`
+	Users         []usersDataSourceUserModel `tfsdk:"users"`
+}
+
+type usersFilterModel struct {
+	Name      types.String `tfsdk:"name"`
+	GroupID   types.String `tfsdk:"group_id"`
+	GroupName types.String `tfsdk:"group_name"`
+}
+
+type usersDataSourceUserModel struct {
+	ID     types.String `tfsdk:"id"`
+	Name   types.String `tfsdk:"name"`
+	Groups []string     `tfsdk:"groups"`
+}
+
+func dataSourceUsers() datasource.DataSource {
+	return &usersDataSource{}
+}
+
+func (d *usersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (d *usersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Get a list of Infra users.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"include_groups": schema.BoolAttribute{
+				MarkdownDescription: "Include each user's group membership. Default is `false`.",
+				Optional:            true,
+			},
+			"users": schema.ListAttribute{
+				MarkdownDescription: "The users matching the filter.",
+				Computed:            true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"id":     types.StringType,
+						"name":   types.StringType,
+						"groups": types.ListType{ElemType: types.StringType},
 					},
 				},
 			},
-			"include_groups": &schema.Schema{
-				Description: "Include each user's group membership. Default is `false`.",
-				Type:        schema.TypeBool,
-				Optional:    true,
-			},
-			"users": &schema.Schema{
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": &schema.Schema{
-							Description: "The ID of the user.",
-							Type:        schema.TypeString,
-							Computed:    true,
+		},
+
+		Blocks: map[string]schema.Block{
+			"filter": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the user.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringIsEmail(),
+							},
 						},
-						"name": &schema.Schema{
-							Description: "The name of the user.",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"group_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the group where user is a member. Cannot be used with `group_name`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringIsID(),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("group_name")),
+							},
 						},
-						"groups": &schema.Schema{
-							Description: "The groups the user is a member of.",
-							Type:        schema.TypeList,
-							Computed:    true,
-							Elem:        schema.TypeString,
+						"group_name": schema.StringAttribute{
+							MarkdownDescription: "The name of the group where user is a member. Cannot be used with `group_id`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("group_id")),
+							},
 						},
 					},
 				},
@@ -85,8 +110,15 @@ func dataSourceUsers() *schema.Resource {
 	}
 }
 
-func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+func (d *usersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	client := d.meta.client
+
+	var data usersDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	request := api.ListUsersRequest{
 		PaginationRequest: api.PaginationRequest{
@@ -94,64 +126,61 @@ func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m any) dia
 		},
 	}
 
-	for i := range d.Get("filter").([]interface{}) {
-		request.Name = d.Get(fmt.Sprintf("filter.%d.name", i)).(string)
+	for _, filter := range data.Filter {
+		request.Name = filter.Name.ValueString()
 
-		if d.Get(fmt.Sprintf("filter.%d.group_id", i)).(string) != "" || d.Get(fmt.Sprintf("filter.%d.group_name", i)).(string) != "" {
-			group, err := groupFromIDOrName(ctx, client, d, fmt.Sprintf("filter.%d.group_id", i), fmt.Sprintf("filter.%d.group_name", i))
-			if err != nil {
-				return diag.FromErr(err)
-			}
+		var group *api.Group
+		var err error
 
+		switch {
+		case filter.GroupID.ValueString() != "":
+			group, err = groupFromID(ctx, client, filter.GroupID.ValueString())
+		case filter.GroupName.ValueString() != "":
+			group, err = groupFromName(ctx, client, filter.GroupName.ValueString())
+		}
+
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+			return
+		}
+
+		if group != nil {
 			request.Group = group.ID
 		}
 	}
 
 	response, err := client.ListUsers(ctx, request)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+		return
 	}
 
 	sha1sum := sha1.New()
 
-	users := make([]map[string]interface{}, 0, response.Count)
+	data.Users = make([]usersDataSourceUserModel, 0, response.Count)
 	for _, item := range response.Items {
-		user := make(map[string]interface{})
-		user["id"] = item.ID.String()
-		user["name"] = item.Name
+		user := usersDataSourceUserModel{
+			ID:     types.StringValue(item.ID.String()),
+			Name:   types.StringValue(item.Name),
+			Groups: []string{},
+		}
 
 		io.WriteString(sha1sum, item.ID.String())
 
-		if d.Get("include_groups").(bool) {
-			request := api.ListGroupsRequest{
-				UserID: item.ID,
-				PaginationRequest: api.PaginationRequest{
-					Limit: 1000,
-				},
-			}
-
-			response, err := client.ListGroups(ctx, request)
+		if data.IncludeGroups.ValueBool() {
+			groups, err := userGroups(ctx, client, item.ID)
 			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			groups := make([]string, 0, response.Count)
-			for _, group := range response.Items {
-				groups = append(groups, group.Name)
+				resp.Diagnostics.Append(apiErrorFrameworkDiagnostics(err)...)
+				return
 			}
 
-			user["groups"] = groups
+			user.Groups = groups
 		}
 
-		users = append(users, user)
-	}
-
-	if err := d.Set("users", users); err != nil {
-		return diag.FromErr(err)
+		data.Users = append(data.Users, user)
 	}
 
-	d.SetId(hex.EncodeToString(sha1sum.Sum(nil)))
+	data.ID = types.StringValue(hex.EncodeToString(sha1sum.Sum(nil)))
 
-	var diags diag.Diagnostics
-	return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
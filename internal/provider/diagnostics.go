@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+
+	"github.com/infrahq/infra/api"
+)
+
+// apiFieldPaths maps the field names in Infra API validation errors to the path of the
+// resource attribute which sets the field.
+type apiFieldPaths map[string]cty.Path
+
+// apiErrorHints are added to the details of API errors with these status codes.
+var apiErrorHints = map[int32]string{
+	http.StatusUnauthorized: "The access key is not valid. Check that `access_key` or `INFRA_ACCESS_KEY` is set to an access key which has not expired or been deleted.",
+	http.StatusForbidden:    "The access key does not have permission for this operation. Managing users, groups, grants, identity providers and access keys requires the Infra `admin` role.",
+	http.StatusConflict:     "The object already exists in Infra. Import it with `terraform import`, or set `adopt_existing = true` on `infra_user` and `infra_group` resources, to manage it with Terraform.",
+}
+
+// apiErrorDiagnostics returns the diagnostics for an error from the Infra API. Each field
+// error is reported on the attribute path for the field in fields, if there is one.
+func apiErrorDiagnostics(err error, fields apiFieldPaths) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var apiError api.Error
+	if !errors.As(err, &apiError) {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  err.Error(),
+		})
+	}
+
+	for _, fieldError := range apiError.FieldErrors {
+		diagnostic := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Invalid %s", fieldError.FieldName),
+			Detail:   strings.Join(fieldError.Errors, "\n"),
+		}
+
+		if path, ok := fields[fieldError.FieldName]; ok {
+			diagnostic.Summary = "Invalid attribute value"
+			diagnostic.AttributePath = path
+		}
+
+		diags = append(diags, diagnostic)
+	}
+
+	if len(diags) > 0 {
+		return diags
+	}
+
+	detail := fmt.Sprintf("Infra returned %d %s.", apiError.Code, http.StatusText(int(apiError.Code)))
+	if hint, ok := apiErrorHints[apiError.Code]; ok {
+		detail = fmt.Sprintf("%s %s", detail, hint)
+	}
+
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  apiError.Error(),
+		Detail:   detail,
+	})
+}
+
+// apiErrorFrameworkDiagnostics returns the diagnostics of apiErrorDiagnostics for data
+// sources, which are served by terraform-plugin-framework. Data sources have no attributes
+// which are sent to Infra, so field errors are not reported on an attribute path.
+func apiErrorFrameworkDiagnostics(err error) fwdiag.Diagnostics {
+	var diags fwdiag.Diagnostics
+
+	for _, diagnostic := range apiErrorDiagnostics(err, nil) {
+		diags.AddError(diagnostic.Summary, diagnostic.Detail)
+	}
+
+	return diags
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
+)
+
+func TestAPIErrorDiagnostics(t *testing.T) {
+	fields := apiFieldPaths{
+		"name": cty.GetAttrPath("name"),
+	}
+
+	type testCase struct {
+		err      error
+		expected diag.Diagnostics
+	}
+
+	testCases := map[string]testCase{
+		"not an API error": {
+			err: errors.New("connection refused"),
+			expected: diag.Diagnostics{
+				{Severity: diag.Error, Summary: "connection refused"},
+			},
+		},
+		"unauthorized": {
+			err: api.Error{Code: http.StatusUnauthorized, Message: "unauthorized"},
+			expected: diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  "unauthorized",
+					Detail:   "Infra returned 401 Unauthorized. " + apiErrorHints[http.StatusUnauthorized],
+				},
+			},
+		},
+		"forbidden": {
+			err: fmtWrap(api.Error{Code: http.StatusForbidden, Message: "forbidden"}),
+			expected: diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  "forbidden",
+					Detail:   "Infra returned 403 Forbidden. " + apiErrorHints[http.StatusForbidden],
+				},
+			},
+		},
+		"conflict": {
+			err: api.Error{Code: http.StatusConflict, Message: "a user with that name already exists"},
+			expected: diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  "a user with that name already exists",
+					Detail:   "Infra returned 409 Conflict. " + apiErrorHints[http.StatusConflict],
+				},
+			},
+		},
+		"no hint": {
+			err: api.Error{Code: http.StatusInternalServerError},
+			expected: diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  "500 internal server error",
+					Detail:   "Infra returned 500 Internal Server Error.",
+				},
+			},
+		},
+		"field errors": {
+			err: api.Error{
+				Code:    http.StatusBadRequest,
+				Message: "validation failed",
+				FieldErrors: []api.FieldError{
+					{FieldName: "name", Errors: []string{"is required", "must be an email address"}},
+					{FieldName: "kind", Errors: []string{"must be one of (oidc, okta)"}},
+				},
+			},
+			expected: diag.Diagnostics{
+				{
+					Severity:      diag.Error,
+					Summary:       "Invalid attribute value",
+					Detail:        "is required\nmust be an email address",
+					AttributePath: cty.GetAttrPath("name"),
+				},
+				{
+					Severity: diag.Error,
+					Summary:  "Invalid kind",
+					Detail:   "must be one of (oidc, okta)",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := apiErrorDiagnostics(tc.err, fields)
+			assert.DeepEqual(t, actual, tc.expected, cmp.Comparer(func(a, b cty.Path) bool {
+				return a.Equals(b)
+			}))
+		})
+	}
+}
+
+func TestAPIErrorFrameworkDiagnostics(t *testing.T) {
+	err := fmtWrap(api.Error{Code: http.StatusForbidden, Message: "forbidden"})
+
+	var expected fwdiag.Diagnostics
+	expected.AddError("forbidden", "Infra returned 403 Forbidden. "+apiErrorHints[http.StatusForbidden])
+
+	assert.DeepEqual(t, apiErrorFrameworkDiagnostics(err), expected)
+}
+
+func fmtWrap(err error) error {
+	return fmt.Errorf("request failed: %w", err)
+}
@@ -0,0 +1,620 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+// Generate writes Terraform configuration and import blocks for the users, groups, group
+// members, grants and identity providers of an existing Infra organization to dir. The
+// provider is configured from the environment, e.g. INFRA_HOST and INFRA_ACCESS_KEY.
+// Objects which cannot be managed by Terraform are reported to out.
+func Generate(ctx context.Context, dir string, out io.Writer) error {
+	p := New()
+
+	if diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		var errs []string
+		for _, d := range diags {
+			errs = append(errs, d.Summary)
+		}
+
+		return errors.New(strings.Join(errs, ": "))
+	}
+
+	return generate(ctx, p.Meta().(*providerMeta).client, p.ResourcesMap, dir, out)
+}
+
+const generatedFileHeader = `# Generated by terraform-provider-infra generate. Review this configuration and run
+# "terraform plan" to import the existing objects before making any changes.
+`
+
+func generate(ctx context.Context, client *api.Client, resources map[string]*schema.Resource, dir string, out io.Writer) error {
+	g := &generator{
+		client:    client,
+		resources: resources,
+		out:       out,
+		files:     make(map[string]*hclwrite.File),
+		names:     make(map[string]map[string]bool),
+		users:     make(map[uid.ID]string),
+		groups:    make(map[uid.ID]string),
+	}
+
+	steps := []func(context.Context) error{
+		g.generateUsers,
+		g.generateGroups,
+		g.generateGrants,
+		g.generateIdentityProviders,
+		g.reportAccessKeys,
+	}
+
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return err
+		}
+
+		if g.err != nil {
+			return fmt.Errorf("the generator does not match the provider schema: %w", g.err)
+		}
+	}
+
+	return g.write(dir)
+}
+
+// generator builds the configuration for each kind of object in its own file. Attributes
+// are checked against the provider's resource schemas as they are set so the generated
+// configuration matches the provider.
+type generator struct {
+	client    *api.Client
+	resources map[string]*schema.Resource
+	out       io.Writer
+
+	files     map[string]*hclwrite.File
+	fileNames []string
+
+	// names are the resource names used for each resource type
+	names map[string]map[string]bool
+
+	// users and groups are the resource names of the generated users and groups
+	users  map[uid.ID]string
+	groups map[uid.ID]string
+
+	// err is the first mismatch between the generator and the resource schemas
+	err error
+}
+
+// fail records err if it is the first error. Blocks keep accepting attributes after an
+// error so each step can finish before the error is returned.
+func (g *generator) fail(err error) {
+	if g.err == nil {
+		g.err = err
+	}
+}
+
+// listEvery returns the items from every page like listAll, and returns an error if the
+// number of items does not match the total reported by Infra, e.g. because objects were
+// created or deleted while the pages were read. The generated configuration includes
+// authoritative resources, which must not be generated from a partial list.
+func listEvery[T any](list func(page int) (*api.ListResponse[T], error)) ([]T, error) {
+	var total int
+
+	items, err := listAll(func(page int) (*api.ListResponse[T], error) {
+		response, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		total = response.TotalCount
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) != total {
+		return nil, fmt.Errorf("read %d of %d items: the list changed while it was read, run generate again", len(items), total)
+	}
+
+	return items, nil
+}
+
+func (g *generator) generateUsers(ctx context.Context) error {
+	users, err := listEvery(func(page int) (*api.ListResponse[api.User], error) {
+		return g.client.ListUsers(ctx, api.ListUsersRequest{
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list users: %w", err)
+	}
+
+	for _, user := range users {
+		r := g.resource("users.tf", "infra_user", user.Name)
+		r.setValue("name", cty.StringVal(user.Name))
+		g.importBlock("users.tf", r, user.ID.String())
+
+		g.users[user.ID] = r.name
+	}
+
+	return nil
+}
+
+func (g *generator) generateGroups(ctx context.Context) error {
+	groups, err := listEvery(func(page int) (*api.ListResponse[api.Group], error) {
+		return g.client.ListGroups(ctx, api.ListGroupsRequest{
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list groups: %w", err)
+	}
+
+	for _, group := range groups {
+		r := g.resource("groups.tf", "infra_group", group.Name)
+		r.setValue("name", cty.StringVal(group.Name))
+		g.importBlock("groups.tf", r, group.ID.String())
+
+		g.groups[group.ID] = r.name
+
+		members, err := listEvery(func(page int) (*api.ListResponse[api.User], error) {
+			return g.client.ListUsers(ctx, api.ListUsersRequest{
+				Group: group.ID,
+				PaginationRequest: api.PaginationRequest{
+					Page:  page,
+					Limit: 1000,
+				},
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("list members of group %s: %w", group.Name, err)
+		}
+
+		if len(members) == 0 {
+			continue
+		}
+
+		userIDs := make([]hclwrite.Tokens, 0, len(members))
+		for _, member := range members {
+			userIDs = append(userIDs, g.userIDTokens(member.ID))
+		}
+
+		m := g.resource("group_members.tf", "infra_group_members", group.Name)
+		m.setTokens("group_id", referenceTokens("infra_group", r.name, "id"))
+		m.setTokens("user_ids", hclwrite.TokensForTuple(userIDs))
+		g.importBlock("group_members.tf", m, group.ID.String())
+	}
+
+	return nil
+}
+
+// generateGrants generates an infra_resource_grants resource for each Kubernetes cluster
+// and namespace with grants, and an infra_grant resource for each other grant.
+func (g *generator) generateGrants(ctx context.Context) error {
+	destinations, err := listEvery(func(page int) (*api.ListResponse[api.Destination], error) {
+		return g.client.ListDestinations(ctx, api.ListDestinationsRequest{
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list destinations: %w", err)
+	}
+
+	kinds := make(map[string]string, len(destinations))
+	for _, destination := range destinations {
+		kinds[destination.Name] = destination.Kind
+	}
+
+	grants, err := listEvery(func(page int) (*api.ListResponse[api.Grant], error) {
+		return g.client.ListGrants(ctx, api.ListGrantsRequest{
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list grants: %w", err)
+	}
+
+	var resources []string
+	resourceGrants := make(map[string][]api.Grant)
+
+	for _, grant := range grants {
+		if _, _, ok := g.subject(grant); !ok {
+			fmt.Fprintf(g.out, "Skipping grant %s: the user or group is not managed by the generated configuration\n", grant.ID)
+			continue
+		}
+
+		name, _, _ := strings.Cut(grant.Resource, ".")
+		if grant.Resource == "infra" || kinds[name] != "kubernetes" {
+			g.generateGrant(grant)
+			continue
+		}
+
+		if _, ok := resourceGrants[grant.Resource]; !ok {
+			resources = append(resources, grant.Resource)
+		}
+
+		resourceGrants[grant.Resource] = append(resourceGrants[grant.Resource], grant)
+	}
+
+	for _, resource := range resources {
+		r := g.resource("grants.tf", "infra_resource_grants", resource)
+
+		cluster, namespace, _ := strings.Cut(resource, ".")
+		r.setValue("cluster", cty.StringVal(cluster))
+		if namespace != "" {
+			r.setValue("namespace", cty.StringVal(namespace))
+		}
+
+		for _, grant := range resourceGrants[resource] {
+			key, tokens, _ := g.subject(grant)
+
+			b := r.block("grant")
+			b.setTokens(key, tokens)
+			b.setValue("role", cty.StringVal(grant.Privilege))
+		}
+
+		g.importBlock("grants.tf", r, resource)
+	}
+
+	return nil
+}
+
+// generateGrant generates an infra_grant resource and its import block. Without the import
+// block, the created resource would adopt the existing grant instead of managing it.
+func (g *generator) generateGrant(grant api.Grant) {
+	key, tokens, _ := g.subject(grant)
+
+	subject := g.users[grant.User]
+	if key == "group_id" {
+		subject = g.groups[grant.Group]
+	}
+
+	r := g.resource("grants.tf", "infra_grant", strings.Join([]string{subject, grant.Resource, grant.Privilege}, "_"))
+	r.setTokens(key, tokens)
+
+	g.importBlock("grants.tf", r, grant.ID.String())
+
+	if grant.Resource == "infra" {
+		r.block("infra").setValue("role", cty.StringVal(grant.Privilege))
+
+		return
+	}
+
+	name, resource, _ := strings.Cut(grant.Resource, ".")
+
+	destination := r.block("destination")
+	destination.setValue("name", cty.StringVal(name))
+	destination.setValue("role", cty.StringVal(grant.Privilege))
+
+	if resource != "" {
+		destination.setValue("resource", cty.StringVal(resource))
+	}
+}
+
+// subject returns the attribute and reference for the user or group of a grant. ok is false
+// if the user or group is not part of the generated configuration, e.g. a system identity.
+func (g *generator) subject(grant api.Grant) (key string, tokens hclwrite.Tokens, ok bool) {
+	if name, ok := g.users[grant.User]; ok {
+		return "user_id", referenceTokens("infra_user", name, "id"), true
+	}
+
+	if name, ok := g.groups[grant.Group]; ok {
+		return "group_id", referenceTokens("infra_group", name, "id"), true
+	}
+
+	return "", nil, false
+}
+
+func (g *generator) userIDTokens(id uid.ID) hclwrite.Tokens {
+	if name, ok := g.users[id]; ok {
+		return referenceTokens("infra_user", name, "id")
+	}
+
+	return hclwrite.TokensForValue(cty.StringVal(id.String()))
+}
+
+// generateIdentityProviders generates an infra_identity_provider resource for each identity
+// provider. Client secrets cannot be read from Infra so each one is set from a variable.
+func (g *generator) generateIdentityProviders(ctx context.Context) error {
+	providers, err := listEvery(func(page int) (*api.ListResponse[api.Provider], error) {
+		return g.client.ListProviders(ctx, api.ListProvidersRequest{
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list identity providers: %w", err)
+	}
+
+	for _, provider := range providers {
+		// the built-in infra provider is not configurable
+		if provider.Kind == "infra" {
+			continue
+		}
+
+		r := g.resource("identity_providers.tf", "infra_identity_provider", provider.Name)
+
+		variable := r.name + "_client_secret"
+
+		r.setValue("name", cty.StringVal(provider.Name))
+		r.setValue("client_id", cty.StringVal(provider.ClientID))
+		r.setTokens("client_secret", referenceTokens("var", variable))
+
+		providerURL := provider.URL
+		if !strings.HasPrefix(providerURL, "https://") {
+			providerURL = fmt.Sprintf("https://%s", providerURL)
+		}
+
+		switch provider.Kind {
+		case "azure":
+			// the URL is https://login.microsoftonline.com/<tenant_id>/v2.0
+			parts := strings.Split(strings.TrimPrefix(providerURL, "https://"), "/")
+			if len(parts) < 2 {
+				return fmt.Errorf("identity provider %s: unexpected Azure URL %q", provider.Name, provider.URL)
+			}
+
+			r.block("azure").setValue("tenant_id", cty.StringVal(parts[1]))
+		case "google":
+			// the Google workspace credentials cannot be read from Infra
+			r.block("google")
+		case "okta":
+			r.block("okta").setValue("issuer", cty.StringVal(providerURL))
+		default:
+			r.setValue("issuer", cty.StringVal(providerURL))
+		}
+
+		g.importBlock("identity_providers.tf", r, provider.ID.String())
+
+		body := g.file("identity_providers.tf").Body()
+		body.AppendNewline()
+
+		v := body.AppendNewBlock("variable", []string{variable}).Body()
+		v.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("The OIDC client secret of the %s identity provider.", provider.Name)))
+		v.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+		v.SetAttributeValue("sensitive", cty.True)
+
+		if provider.Kind == "google" {
+			fmt.Fprintf(g.out, "Identity provider %s: set google.admin_email and google.service_account_key to sync Google groups\n", provider.Name)
+		}
+	}
+
+	return nil
+}
+
+// reportAccessKeys reports the access keys which are not generated. infra_access_key cannot
+// be imported because the secret of an existing access key cannot be read from Infra.
+func (g *generator) reportAccessKeys(ctx context.Context) error {
+	keys, err := listEvery(func(page int) (*api.ListResponse[api.AccessKey], error) {
+		return g.client.ListAccessKeys(ctx, api.ListAccessKeysRequest{
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list access keys: %w", err)
+	}
+
+	for _, key := range keys {
+		fmt.Fprintf(g.out, "Skipping access key %s issued for %s: access keys cannot be imported\n", key.Name, key.IssuedForName)
+	}
+
+	return nil
+}
+
+func (g *generator) file(name string) *hclwrite.File {
+	f, ok := g.files[name]
+	if !ok {
+		f = hclwrite.NewEmptyFile()
+		f.Body().AppendUnstructuredTokens(hclwrite.Tokens{
+			{Type: hclsyntax.TokenComment, Bytes: []byte(generatedFileHeader)},
+		})
+
+		g.files[name] = f
+		g.fileNames = append(g.fileNames, name)
+	}
+
+	return f
+}
+
+// resource appends a resource block to the file, named after label.
+func (g *generator) resource(file, typeName, label string) *generatedBlock {
+	resource, ok := g.resources[typeName]
+	if !ok {
+		g.fail(fmt.Errorf("the provider does not have the %s resource", typeName))
+		return g.detachedBlock(typeName)
+	}
+
+	if g.names[typeName] == nil {
+		g.names[typeName] = make(map[string]bool)
+	}
+
+	name := resourceName(label)
+	for i := 2; g.names[typeName][name]; i++ {
+		name = fmt.Sprintf("%s_%d", resourceName(label), i)
+	}
+
+	g.names[typeName][name] = true
+
+	body := g.file(file).Body()
+	body.AppendNewline()
+
+	return &generatedBlock{
+		g:        g,
+		typeName: typeName,
+		name:     name,
+		schema:   resource.Schema,
+		body:     body.AppendNewBlock("resource", []string{typeName, name}).Body(),
+	}
+}
+
+// detachedBlock returns a block which is not written to any file, for use after an error.
+func (g *generator) detachedBlock(typeName string) *generatedBlock {
+	return &generatedBlock{
+		g:        g,
+		typeName: typeName,
+		body:     hclwrite.NewEmptyFile().Body(),
+	}
+}
+
+// importBlock appends an import block for r to the file. The resource must be importable,
+// otherwise applying the configuration would create it instead.
+func (g *generator) importBlock(file string, r *generatedBlock, id string) {
+	if resource, ok := g.resources[r.typeName]; ok && resource.Importer == nil {
+		g.fail(fmt.Errorf("%s cannot be imported", r.typeName))
+		return
+	}
+
+	body := g.file(file).Body()
+	body.AppendNewline()
+
+	b := body.AppendNewBlock("import", nil).Body()
+	b.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: r.typeName},
+		hcl.TraverseAttr{Name: r.name},
+	})
+	b.SetAttributeValue("id", cty.StringVal(id))
+}
+
+// write creates the generated files in dir. Existing files are not overwritten.
+func (g *generator) write(dir string) error {
+	sort.Strings(g.fileNames)
+
+	for _, name := range g.fileNames {
+		path := filepath.Join(dir, name)
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+
+		if _, err := f.Write(hclwrite.Format(g.files[name].Bytes())); err != nil {
+			f.Close()
+			return err
+		}
+
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(g.out, "Wrote %s\n", path)
+	}
+
+	return nil
+}
+
+// generatedBlock is a resource or nested block whose attributes are checked against the
+// schema of the resource. A mismatch is recorded in the generator.
+type generatedBlock struct {
+	g        *generator
+	typeName string
+	name     string
+	schema   map[string]*schema.Schema
+	body     *hclwrite.Body
+}
+
+// attribute returns the schema of a configurable attribute. The generated attributes are
+// fixed, so a missing attribute means the generator is out of date with the schema.
+func (b *generatedBlock) attribute(key string) (*schema.Schema, bool) {
+	s, ok := b.schema[key]
+	if !ok || !(s.Required || s.Optional) {
+		b.g.fail(fmt.Errorf("%s does not have a configurable attribute %s", b.typeName, key))
+		return nil, false
+	}
+
+	return s, true
+}
+
+func (b *generatedBlock) setValue(key string, value cty.Value) {
+	if _, ok := b.attribute(key); ok {
+		b.body.SetAttributeValue(key, value)
+	}
+}
+
+func (b *generatedBlock) setTokens(key string, tokens hclwrite.Tokens) {
+	if _, ok := b.attribute(key); ok {
+		b.body.SetAttributeRaw(key, tokens)
+	}
+}
+
+// block appends a nested block.
+func (b *generatedBlock) block(key string) *generatedBlock {
+	s, ok := b.attribute(key)
+	if !ok {
+		return b.g.detachedBlock(b.typeName)
+	}
+
+	resource, ok := s.Elem.(*schema.Resource)
+	if !ok {
+		b.g.fail(fmt.Errorf("%s attribute %s is not a block", b.typeName, key))
+		return b.g.detachedBlock(b.typeName)
+	}
+
+	return &generatedBlock{
+		g:        b.g,
+		typeName: b.typeName,
+		schema:   resource.Schema,
+		body:     b.body.AppendNewBlock(key, nil).Body(),
+	}
+}
+
+// referenceTokens returns the tokens of a reference such as infra_user.alice.id.
+func referenceTokens(root string, attrs ...string) hclwrite.Tokens {
+	traversal := hcl.Traversal{hcl.TraverseRoot{Name: root}}
+	for _, attr := range attrs {
+		traversal = append(traversal, hcl.TraverseAttr{Name: attr})
+	}
+
+	return hclwrite.TokensForTraversal(traversal)
+}
+
+// resourceName returns a Terraform resource name for s, e.g. alice_example_com for
+// alice@example.com.
+func resourceName(s string) string {
+	var sb strings.Builder
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+
+	name := sb.String()
+	if name == "" || !(name[0] >= 'a' && name[0] <= 'z' || name[0] == '_') {
+		name = "_" + name
+	}
+
+	return name
+}
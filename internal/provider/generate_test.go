@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+func TestGenerate(t *testing.T) {
+	alice := api.User{ID: uid.ID(1001), Name: "alice@example.com"}
+	bob := api.User{ID: uid.ID(1002), Name: "bob@example.com"}
+	connector := uid.ID(1003)
+	developers := api.Group{ID: uid.ID(2001), Name: "developers"}
+	admins := api.Group{ID: uid.ID(2002), Name: "Admins"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
+		var users []api.User
+		switch r.URL.Query().Get("group") {
+		case "", "0":
+			users = []api.User{alice, bob}
+		case developers.ID.String():
+			users = []api.User{alice, bob}
+		}
+
+		testWriteJSON(t, w, http.StatusOK, testListResponse(users...))
+	})
+	mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, testListResponse(developers, admins))
+	})
+	mux.HandleFunc("/api/destinations", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, testListResponse(
+			api.Destination{Name: "production", Kind: "kubernetes"},
+			api.Destination{Name: "bastion", Kind: "ssh"},
+		))
+	})
+	mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+		grants := []api.Grant{
+			{ID: uid.ID(3001), Group: admins.ID, Privilege: "admin", Resource: "infra"},
+			{ID: uid.ID(3002), User: alice.ID, Privilege: "view", Resource: "infra"},
+			{ID: uid.ID(3003), Group: developers.ID, Privilege: "edit", Resource: "production.default"},
+			{ID: uid.ID(3004), User: bob.ID, Privilege: "view", Resource: "production.default"},
+			{ID: uid.ID(3005), Group: admins.ID, Privilege: "cluster-admin", Resource: "production"},
+			{ID: uid.ID(3006), User: alice.ID, Privilege: "connect", Resource: "bastion"},
+			{ID: uid.ID(3007), User: connector, Privilege: "view", Resource: "production"},
+		}
+
+		testWriteJSON(t, w, http.StatusOK, testListResponse(grants...))
+	})
+	mux.HandleFunc("/api/providers", func(w http.ResponseWriter, r *http.Request) {
+		providers := []api.Provider{
+			{ID: uid.ID(4001), Name: "infra", Kind: "infra"},
+			{ID: uid.ID(4002), Name: "okta", Kind: "okta", URL: "example.okta.com", ClientID: "okta-client"},
+			{ID: uid.ID(4003), Name: "azure", Kind: "azure", URL: "login.microsoftonline.com/tenant/v2.0", ClientID: "azure-client"},
+			{ID: uid.ID(4004), Name: "google", Kind: "google", URL: "accounts.google.com", ClientID: "google-client"},
+			{ID: uid.ID(4005), Name: "oidc", Kind: "oidc", URL: "https://idp.example.com", ClientID: "oidc-client"},
+		}
+
+		testWriteJSON(t, w, http.StatusOK, testListResponse(providers...))
+	})
+	mux.HandleFunc("/api/access-keys", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, testListResponse(
+			api.AccessKey{ID: uid.ID(5001), Name: "ci", IssuedFor: connector, IssuedForName: "connector"},
+		))
+	})
+
+	meta := testProviderMeta(t, mux)
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+
+	err := generate(context.Background(), meta.client, New().ResourcesMap, dir, &out)
+	assert.NilError(t, err)
+
+	for _, name := range []string{"users.tf", "groups.tf", "group_members.tf", "grants.tf", "identity_providers.tf"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NilError(t, err)
+
+		golden.Assert(t, string(b), filepath.Join("generate", name))
+	}
+
+	assert.Equal(t, out.String(), `Skipping grant `+uid.ID(3007).String()+`: the user or group is not managed by the generated configuration
+Identity provider google: set google.admin_email and google.service_account_key to sync Google groups
+Skipping access key ci issued for connector: access keys cannot be imported
+Wrote `+filepath.Join(dir, "grants.tf")+`
+Wrote `+filepath.Join(dir, "group_members.tf")+`
+Wrote `+filepath.Join(dir, "groups.tf")+`
+Wrote `+filepath.Join(dir, "identity_providers.tf")+`
+Wrote `+filepath.Join(dir, "users.tf")+`
+`)
+
+	t.Run("existing files are not overwritten", func(t *testing.T) {
+		err := generate(context.Background(), meta.client, New().ResourcesMap, dir, &out)
+		assert.ErrorIs(t, err, os.ErrExist)
+	})
+
+	t.Run("schema mismatch", func(t *testing.T) {
+		cases := map[string]struct {
+			change   func(resources map[string]*schema.Resource)
+			expected string
+		}{
+			"resource": {
+				change: func(resources map[string]*schema.Resource) {
+					delete(resources, "infra_identity_provider")
+				},
+				expected: "the provider does not have the infra_identity_provider resource",
+			},
+			"attribute": {
+				change: func(resources map[string]*schema.Resource) {
+					delete(resources["infra_grant"].Schema, "infra")
+				},
+				expected: "infra_grant does not have a configurable attribute infra",
+			},
+			"importer": {
+				change: func(resources map[string]*schema.Resource) {
+					resources["infra_grant"].Importer = nil
+				},
+				expected: "infra_grant cannot be imported",
+			},
+		}
+
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				resources := New().ResourcesMap
+				tc.change(resources)
+
+				dir := t.TempDir()
+
+				err := generate(context.Background(), meta.client, resources, dir, io.Discard)
+				assert.ErrorContains(t, err, tc.expected)
+
+				entries, err := os.ReadDir(dir)
+				assert.NilError(t, err)
+				assert.Equal(t, len(entries), 0)
+			})
+		}
+	})
+}
+
+func TestListEvery(t *testing.T) {
+	pages := map[int]*api.ListResponse[string]{
+		1: {Items: []string{"a", "b"}, PaginationResponse: api.PaginationResponse{Page: 1, TotalPages: 2, TotalCount: 3}},
+		2: {Items: []string{"c"}, PaginationResponse: api.PaginationResponse{Page: 2, TotalPages: 2, TotalCount: 3}},
+	}
+
+	items, err := listEvery(func(page int) (*api.ListResponse[string], error) {
+		return pages[page], nil
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, []string{"a", "b", "c"})
+
+	t.Run("changed while listing", func(t *testing.T) {
+		pages[2].TotalCount = 4
+
+		_, err := listEvery(func(page int) (*api.ListResponse[string], error) {
+			return pages[page], nil
+		})
+		assert.ErrorContains(t, err, "read 3 of 4 items")
+	})
+}
+
+// testListResponse returns a single page list response with items.
+func testListResponse[T any](items ...T) api.ListResponse[T] {
+	return api.ListResponse[T]{
+		Count: len(items),
+		Items: items,
+		PaginationResponse: api.PaginationResponse{
+			Page:       1,
+			TotalPages: 1,
+			TotalCount: len(items),
+		},
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	tests := map[string]string{
+		"alice@example.com":  "alice_example_com",
+		"Developers":         "developers",
+		"production.default": "production_default",
+		"my-group":           "my-group",
+		"1password":          "_1password",
+		"":                   "_",
+	}
+
+	for s, expected := range tests {
+		assert.Equal(t, resourceName(s), expected, s)
+	}
+}
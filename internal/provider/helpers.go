@@ -1,21 +1,32 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/pem"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/infrahq/infra/api"
 	"github.com/infrahq/infra/uid"
 )
 
+// defaultTimeout is the default time allowed for each resource operation. Operations may
+// make several requests, each limited by the provider's `request_timeout`.
+const defaultTimeout = 5 * time.Minute
+
 func ParseID(d *schema.ResourceData, key string) (uid.ID, error) {
 	return uid.Parse([]byte(d.Get(key).(string)))
 }
@@ -29,6 +40,138 @@ func GrantResource(d *schema.ResourceData) string {
 	return resource
 }
 
+// ParseAuthorizedKey parses a single public key in the format of an authorized_keys file.
+func ParseAuthorizedKey(s string) (ssh.PublicKey, error) {
+	key, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(s))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("public key must be in authorized_keys format")
+	case len(bytes.TrimSpace(rest)) > 0:
+		return nil, fmt.Errorf("public key must be only a single key")
+	}
+
+	return key, nil
+}
+
+// hashStateFunc stores the SHA-256 hash of a sensitive value in the state instead of the
+// value. Empty values are stored as is.
+func hashStateFunc(v any) string {
+	s, _ := v.(string)
+	if s == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeOnlyStateFunc stores an empty string in the state instead of the value of a
+// write-only attribute.
+func writeOnlyStateFunc(v any) string {
+	return ""
+}
+
+// writeOnlyString returns the configured value of an attribute which is stored using
+// writeOnlyStateFunc. The value from d.Get is always empty.
+func writeOnlyString(d *schema.ResourceData, key string) string {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return ""
+	}
+
+	v := config.GetAttr(key)
+	if v.IsNull() || !v.IsKnown() {
+		return ""
+	}
+
+	return v.AsString()
+}
+
+// configured returns true if the attribute is set in the configuration.
+func configured(config cty.Value, key string) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+
+	return !config.GetAttr(key).IsNull()
+}
+
+const (
+	passwordLowercase = "abcdefghijklmnopqrstuvwxyz"
+	passwordUppercase = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordNumbers   = "0123456789"
+	passwordSymbols   = "!#$%&*+-.:=?@^_~"
+)
+
+// generatePassword returns a random password which satisfies the password requirements.
+func generatePassword(requirements api.PasswordRequirements) (string, error) {
+	length := 24
+	if requirements.LengthMin > length {
+		length = requirements.LengthMin
+	}
+
+	var password []byte
+
+	for _, set := range []struct {
+		chars string
+		min   int
+	}{
+		{passwordLowercase, requirements.LowercaseMin},
+		{passwordUppercase, requirements.UppercaseMin},
+		{passwordNumbers, requirements.NumberMin},
+		{passwordSymbols, requirements.SymbolMin},
+	} {
+		for i := 0; i < set.min; i++ {
+			c, err := randomChar(set.chars)
+			if err != nil {
+				return "", err
+			}
+
+			password = append(password, c)
+		}
+	}
+
+	for len(password) < length {
+		c, err := randomChar(passwordLowercase + passwordUppercase + passwordNumbers)
+		if err != nil {
+			return "", err
+		}
+
+		password = append(password, c)
+	}
+
+	// shuffle so the required characters are not always at the start
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+
+	return chars[i.Int64()], nil
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 func DurationDiffSuppressFunc() schema.SchemaDiffSuppressFunc {
 	return func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 		oldDuration, err := time.ParseDuration(oldValue)
@@ -45,6 +188,16 @@ func DurationDiffSuppressFunc() schema.SchemaDiffSuppressFunc {
 	}
 }
 
+// FormatTime formats t as a RFC3339 timestamp. A zero time, e.g. an access key that
+// has never been used, is formatted as an empty string.
+func FormatTime(t api.Time) string {
+	if t.Time().IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
 func DecodePEM(data []byte, keytype string) ([]byte, error) {
 	blocks, _ := pem.Decode(data)
 	if blocks == nil || blocks.Type != keytype {
@@ -91,3 +244,62 @@ func requireMinimumServerVersion(ctx context.Context, client *api.Client, minimu
 
 	return nil
 }
+
+// forceNewIfBlocksChange replaces the resource if one of the single item list blocks in
+// keys is added or removed, e.g. when a grant moves from `infra` to `kubernetes`. Changes
+// to the attributes of a block replace the resource only if the attribute is ForceNew.
+func forceNewIfBlocksChange(keys ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m any) error {
+		if d.Id() == "" {
+			return nil
+		}
+
+		for _, key := range keys {
+			oldBlocks, newBlocks := d.GetChange(key)
+			if len(oldBlocks.([]interface{})) == len(newBlocks.([]interface{})) {
+				continue
+			}
+
+			if err := d.ForceNew(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// blockValues returns the string values of a single item list block. It is empty if the
+// block is not set.
+func blockValues(v any) map[string]string {
+	values := make(map[string]string)
+
+	items, _ := v.([]interface{})
+	if len(items) == 0 {
+		return values
+	}
+
+	block, _ := items[0].(map[string]interface{})
+	for key, value := range block {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+
+	return values
+}
+
+// setStateDefaults returns a state upgrader which sets each attribute in defaults that
+// is missing from the state. Attributes added with a default would otherwise be null
+// in existing state and show up as a change in the next plan.
+func setStateDefaults(defaults map[string]interface{}) schema.StateUpgradeFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, meta any) (map[string]interface{}, error) {
+		for key, value := range defaults {
+			if _, ok := rawState[key]; !ok || rawState[key] == nil {
+				rawState[key] = value
+			}
+		}
+
+		return rawState, nil
+	}
+}
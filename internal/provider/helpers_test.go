@@ -2,8 +2,76 @@ package provider
 
 import (
 	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
 )
 
 func composeTestConfigFunc(configs ...string) string {
 	return strings.Join(configs, "\n")
 }
+
+func TestFormatTime(t *testing.T) {
+	assert.Equal(t, FormatTime(api.Time{}), "")
+	assert.Equal(t, FormatTime(api.Time(time.Date(2022, 12, 1, 19, 48, 55, 0, time.UTC))), "2022-12-01T19:48:55Z")
+}
+
+func TestGeneratePassword(t *testing.T) {
+	cases := map[string]api.PasswordRequirements{
+		"default": {},
+		"all": {
+			LowercaseMin: 2,
+			UppercaseMin: 2,
+			NumberMin:    2,
+			SymbolMin:    2,
+			LengthMin:    8,
+		},
+		"long": {
+			SymbolMin: 10,
+			LengthMin: 40,
+		},
+	}
+
+	for name, requirements := range cases {
+		t.Run(name, func(t *testing.T) {
+			password, err := generatePassword(requirements)
+			assert.NilError(t, err)
+
+			assert.Assert(t, len(password) >= 24)
+			assert.Assert(t, len(password) >= requirements.LengthMin)
+
+			count := func(chars string) int {
+				var n int
+				for _, c := range password {
+					if strings.ContainsRune(chars, c) {
+						n++
+					}
+				}
+
+				return n
+			}
+
+			assert.Assert(t, count(passwordLowercase) >= requirements.LowercaseMin)
+			assert.Assert(t, count(passwordUppercase) >= requirements.UppercaseMin)
+			assert.Assert(t, count(passwordNumbers) >= requirements.NumberMin)
+			assert.Assert(t, count(passwordSymbols) >= requirements.SymbolMin)
+		})
+	}
+}
+
+func TestHashStateFunc(t *testing.T) {
+	assert.Equal(t, hashStateFunc(""), "")
+	assert.Equal(t, hashStateFunc("password"), "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8")
+}
+
+func TestBlockValues(t *testing.T) {
+	assert.DeepEqual(t, blockValues(nil), map[string]string{})
+	assert.DeepEqual(t, blockValues([]interface{}{}), map[string]string{})
+	assert.DeepEqual(t, blockValues([]interface{}{nil}), map[string]string{})
+
+	actual := blockValues([]interface{}{map[string]interface{}{"role": "view", "cluster": "example", "namespace": ""}})
+	assert.DeepEqual(t, actual, map[string]string{"role": "view", "cluster": "example", "namespace": ""})
+}
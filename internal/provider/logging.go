@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/infrahq/infra/uid"
+)
+
+// sensitiveJSONKeys are the keys of request and response bodies whose values are masked
+// before they are logged.
+var sensitiveJSONKeys = map[string]bool{
+	"accessKey":       true,
+	"clientSecret":    true,
+	"oldPassword":     true,
+	"oneTimePassword": true,
+	"password":        true,
+	"privateKey":      true,
+	"token":           true,
+}
+
+const maskedValue = "***"
+
+// loggingTransport logs each request to the Infra server with tflog. The method, path,
+// status and duration are logged at DEBUG, and the request and response bodies at TRACE
+// with secrets masked.
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// the access key is also masked if it appears in any other field
+	if accessKey := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); accessKey != "" {
+		ctx = tflog.MaskAllFieldValuesStrings(ctx, accessKey)
+	}
+
+	// Infra does not return a request ID so one is generated to match each response to
+	// its request
+	ctx = tflog.SetField(ctx, "request_id", uid.New().String())
+	ctx = tflog.SetField(ctx, "method", req.Method)
+	ctx = tflog.SetField(ctx, "path", req.URL.Path)
+
+	fields := map[string]interface{}{
+		"query": req.URL.RawQuery,
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(body)
+			fields["body"] = maskJSON(b)
+		}
+	}
+
+	tflog.Debug(ctx, "Sending request to Infra")
+	tflog.Trace(ctx, "Request details", fields)
+
+	start := time.Now()
+
+	resp, err := t.transport.RoundTrip(req)
+
+	ctx = tflog.SetField(ctx, "duration_ms", time.Since(start).Milliseconds())
+
+	if err != nil {
+		tflog.Debug(ctx, "Request to Infra failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+
+		return nil, err
+	}
+
+	ctx = tflog.SetField(ctx, "status", resp.StatusCode)
+
+	tflog.Debug(ctx, "Received response from Infra")
+
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	tflog.Trace(ctx, "Response details", map[string]interface{}{
+		"body": maskJSON(b),
+	})
+
+	return resp, nil
+}
+
+// maskJSON returns the JSON document in b with the values of sensitiveJSONKeys masked. Bodies
+// which are not JSON are not logged since they cannot be masked.
+func maskJSON(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "(not JSON)"
+	}
+
+	masked, err := json.Marshal(maskJSONValue(v))
+	if err != nil {
+		return "(not JSON)"
+	}
+
+	return string(masked)
+}
+
+func maskJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && s != "" && sensitiveJSONKeys[key] {
+				v[key] = maskedValue
+				continue
+			}
+
+			v[key] = maskJSONValue(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = maskJSONValue(value)
+		}
+	}
+
+	return v
+}
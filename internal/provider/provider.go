@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -28,6 +29,18 @@ func normalizeAttributePath(before []string) (after []string) {
 	return after
 }
 
+// providerMeta is the configured provider passed to each resource and data source.
+type providerMeta struct {
+	client *api.Client
+
+	validateGrantTargets bool
+
+	// sharedGrants are the grants which an infra_grant instance has taken over from
+	// another instance during this run, e.g. when a grant is replaced using
+	// `create_before_destroy`. Deleting the old instance must not delete the grant.
+	sharedGrants grantSet
+}
+
 func init() {
 	schema.DescriptionKind = schema.StringMarkdown
 	schema.SchemaDescriptionBuilder = func(s *schema.Schema) string {
@@ -64,9 +77,9 @@ func New() *schema.Provider {
 				ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithHTTPS),
 			},
 			"access_key": &schema.Schema{
-				Description: "The access key used to authenticate with the Infra server. Can also be sourced from `INFRA_ACCESS_KEY`.",
+				Description: "The access key used to authenticate with the Infra server. Can also be sourced from `INFRA_ACCESS_KEY`. One of the two must be set.",
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("INFRA_ACCESS_KEY", nil),
 			},
@@ -102,17 +115,29 @@ func New() *schema.Provider {
 					"server_certificate",
 				},
 			},
-		},
-		DataSourcesMap: map[string]*schema.Resource{
-			"infra_destinations": dataSourceDestinations(),
-			"infra_groups":       dataSourceGroups(),
-			"infra_users":        dataSourceUsers(),
+			"validate_grant_targets": &schema.Schema{
+				Description: "Controls plan-time validation of `infra_grant` Kubernetes configurations. If `true`, the cluster must be an existing destination and the namespace and role must be reported by its connector, otherwise the plan fails with an error. Can also be sourced from `INFRA_VALIDATE_GRANT_TARGETS`. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("INFRA_VALIDATE_GRANT_TARGETS", nil),
+			},
+			"request_timeout": &schema.Schema{
+				Description:      `The maximum amount of time to wait for each request to the Infra server. Format is a duration string, such as "30s" or "2m". A value of "0" disables the timeout. Can also be sourced from ` + "`INFRA_REQUEST_TIMEOUT`. Default is `1m`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFRA_REQUEST_TIMEOUT", "1m"),
+				ValidateDiagFunc: validateStringIsDuration(),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"infra_user":              resourceUser(),
+			"infra_user_public_key":   resourceUserPublicKey(),
 			"infra_group":             resourceGroup(),
 			"infra_group_membership":  resourceGroupMembership(),
+			"infra_group_members":     resourceGroupMembers(),
 			"infra_grant":             resourceGrant(),
+			"infra_grants":            resourceGrants(),
+			"infra_resource_grants":   resourceResourceGrants(),
 			"infra_identity_provider": resourceIdentityProvider(),
 			"infra_access_key":        resourceAccessKey(),
 		},
@@ -121,46 +146,94 @@ func New() *schema.Provider {
 
 func configure() func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
 	return func(ctx context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
-		pool, err := x509.SystemCertPool()
+		requestTimeout, err := time.ParseDuration(d.Get("request_timeout").(string))
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
 
-		if cacert := d.Get("server_certificate").(string); cacert != "" {
-			b, err := DecodePEM([]byte(cacert), "CERTIFICATE")
-			if err != nil {
-				return nil, diag.FromErr(err)
-			}
+		config := providerConfig{
+			Host:                  d.Get("host").(string),
+			AccessKey:             d.Get("access_key").(string),
+			SkipTLSVerify:         d.Get("skip_tls_verify").(bool),
+			ServerCertificate:     d.Get("server_certificate").(string),
+			ServerCertificateFile: d.Get("server_certificate_file").(string),
+			ValidateGrantTargets:  d.Get("validate_grant_targets").(bool),
+			RequestTimeout:        requestTimeout,
+		}
 
-			if ok := pool.AppendCertsFromPEM(b); !ok {
-				return nil, diag.Errorf("not ok %#v", string(b))
-			}
+		meta, err := newProviderMeta(config)
+		if err != nil {
+			return nil, diag.FromErr(err)
 		}
 
-		if cacertfile := d.Get("server_certificate_file").(string); cacertfile != "" {
-			b, err := DecodePEMFile(cacertfile, "CERTIFICATE")
-			if err != nil {
-				return nil, diag.FromErr(err)
-			}
+		return meta, nil
+	}
+}
 
-			if ok := pool.AppendCertsFromPEM(b); !ok {
-				return nil, diag.Errorf("not ok %#v", string(b))
-			}
+// providerConfig is the provider configuration after defaults are applied from the
+// environment. It is shared by the SDK and framework providers.
+type providerConfig struct {
+	Host                  string
+	AccessKey             string
+	SkipTLSVerify         bool
+	ServerCertificate     string
+	ServerCertificateFile string
+	ValidateGrantTargets  bool
+	RequestTimeout        time.Duration
+}
+
+func newProviderMeta(config providerConfig) (*providerMeta, error) {
+	if config.AccessKey == "" {
+		return nil, fmt.Errorf("`access_key` must be set, either in the provider configuration or with `INFRA_ACCESS_KEY`")
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	if cacert := config.ServerCertificate; cacert != "" {
+		b, err := DecodePEM([]byte(cacert), "CERTIFICATE")
+		if err != nil {
+			return nil, err
+		}
+
+		if ok := pool.AppendCertsFromPEM(b); !ok {
+			return nil, fmt.Errorf("not ok %#v", string(b))
 		}
+	}
 
-		return &api.Client{
-			Name:      "terraform",
-			Version:   "0.17.1",
-			URL:       d.Get("host").(string),
-			AccessKey: d.Get("access_key").(string),
-			HTTP: http.Client{
-				Transport: &http.Transport{
+	if cacertfile := config.ServerCertificateFile; cacertfile != "" {
+		b, err := DecodePEMFile(cacertfile, "CERTIFICATE")
+		if err != nil {
+			return nil, err
+		}
+
+		if ok := pool.AppendCertsFromPEM(b); !ok {
+			return nil, fmt.Errorf("not ok %#v", string(b))
+		}
+	}
+
+	client := &api.Client{
+		Name:      "terraform",
+		Version:   "0.17.1",
+		URL:       config.Host,
+		AccessKey: config.AccessKey,
+		HTTP: http.Client{
+			Timeout: config.RequestTimeout,
+			Transport: &loggingTransport{
+				transport: &http.Transport{
 					TLSClientConfig: &tls.Config{
-						InsecureSkipVerify: d.Get("skip_tls_verify").(bool),
+						InsecureSkipVerify: config.SkipTLSVerify,
 						RootCAs:            pool,
 					},
 				},
 			},
-		}, nil
+		},
 	}
+
+	return &providerMeta{
+		client:               client,
+		validateGrantTargets: config.ValidateGrantTargets,
+	}, nil
 }
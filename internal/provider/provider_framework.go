@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// frameworkProvider serves the data sources and resources which have been migrated to
+// terraform-plugin-framework. It is muxed with the SDK provider by NewServer so its schema
+// must be identical to the SDK provider's schema.
+type frameworkProvider struct{}
+
+func NewFramework() provider.Provider {
+	return &frameworkProvider{}
+}
+
+type frameworkProviderModel struct {
+	Host                  types.String `tfsdk:"host"`
+	AccessKey             types.String `tfsdk:"access_key"`
+	SkipTLSVerify         types.Bool   `tfsdk:"skip_tls_verify"`
+	ServerCertificate     types.String `tfsdk:"server_certificate"`
+	ServerCertificateFile types.String `tfsdk:"server_certificate_file"`
+	ValidateGrantTargets  types.Bool   `tfsdk:"validate_grant_targets"`
+	RequestTimeout        types.String `tfsdk:"request_timeout"`
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "infra"
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				MarkdownDescription: "The Infra server instance Terraform will communicate with. Can also be sourced from `INFRA_HOST`. Default is `https://api.infrahq.com`.",
+				Optional:            true,
+			},
+			"access_key": schema.StringAttribute{
+				MarkdownDescription: "The access key used to authenticate with the Infra server. Can also be sourced from `INFRA_ACCESS_KEY`. One of the two must be set.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"skip_tls_verify": schema.BoolAttribute{
+				MarkdownDescription: "Controls client verification of the server certificate. This should only be `true` for testing or development. Can also be sourced from`INFRA_SKIP_TLS_VERIFY`. Cannot be used with `server_certificate`, `server_certificate_file`.",
+				Optional:            true,
+			},
+			"server_certificate": schema.StringAttribute{
+				MarkdownDescription: "The server's PEM-encoded public certificate for client verification. Can also be sourced from `INFRA_SERVER_CERTIFICATE`. Cannot be used with `skip_tls_verify`, `server_certificate_file`.",
+				Optional:            true,
+			},
+			"server_certificate_file": schema.StringAttribute{
+				MarkdownDescription: "The server's PEM-encoded public certificate file for client verification. Can also be sourced from `INFRA_SERVER_CERTIFICATE_FILE`. Cannot be used with `skip_tls_verify`, `server_certificate`.",
+				Optional:            true,
+			},
+			"validate_grant_targets": schema.BoolAttribute{
+				MarkdownDescription: "Controls plan-time validation of `infra_grant` Kubernetes configurations. If `true`, the cluster must be an existing destination and the namespace and role must be reported by its connector, otherwise the plan fails with an error. Can also be sourced from `INFRA_VALIDATE_GRANT_TARGETS`. Default is `false`.",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: `The maximum amount of time to wait for each request to the Infra server. Format is a duration string, such as "30s" or "2m". A value of "0" disables the timeout. Can also be sourced from ` + "`INFRA_REQUEST_TIMEOUT`. Default is `1m`.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// Configure applies the same environment defaults as the SDK provider. Validation is left to
+// the SDK provider since both providers receive the same configuration.
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data frameworkProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	skipTLSVerify, err := boolValueOrEnv(data.SkipTLSVerify, "INFRA_SKIP_TLS_VERIFY")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid skip_tls_verify", err.Error())
+		return
+	}
+
+	validateGrantTargets, err := boolValueOrEnv(data.ValidateGrantTargets, "INFRA_VALIDATE_GRANT_TARGETS")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid validate_grant_targets", err.Error())
+		return
+	}
+
+	requestTimeout, err := time.ParseDuration(stringValueOrEnv(data.RequestTimeout, "INFRA_REQUEST_TIMEOUT", "1m"))
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid request_timeout", err.Error())
+		return
+	}
+
+	config := providerConfig{
+		Host:                  stringValueOrEnv(data.Host, "INFRA_HOST", "https://api.infrahq.com"),
+		AccessKey:             stringValueOrEnv(data.AccessKey, "INFRA_ACCESS_KEY", ""),
+		SkipTLSVerify:         skipTLSVerify,
+		ServerCertificate:     stringValueOrEnv(data.ServerCertificate, "INFRA_SERVER_CERTIFICATE", ""),
+		ServerCertificateFile: stringValueOrEnv(data.ServerCertificateFile, "INFRA_SERVER_CERTIFICATE_FILE", ""),
+		ValidateGrantTargets:  validateGrantTargets,
+		RequestTimeout:        requestTimeout,
+	}
+
+	meta, err := newProviderMeta(config)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to configure provider", err.Error())
+		return
+	}
+
+	resp.DataSourceData = meta
+	resp.ResourceData = meta
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		dataSourceAccessKeys,
+		dataSourceDestination,
+		dataSourceDestinations,
+		dataSourceGroups,
+		dataSourceUsers,
+	}
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+// stringValueOrEnv returns the configured value, or the value of the environment variable
+// key if the attribute is not set, falling back to defaultValue.
+func stringValueOrEnv(value types.String, key, defaultValue string) string {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString()
+	}
+
+	if s, ok := os.LookupEnv(key); ok {
+		return s
+	}
+
+	return defaultValue
+}
+
+func boolValueOrEnv(value types.Bool, key string) (bool, error) {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueBool(), nil
+	}
+
+	if s, ok := os.LookupEnv(key); ok && s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", key, err)
+		}
+
+		return b, nil
+	}
+
+	return false, nil
+}
+
+// frameworkDataSource is embedded in each framework data source to receive the configured
+// provider.
+type frameworkDataSource struct {
+	meta *providerMeta
+}
+
+func (d *frameworkDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	meta, ok := req.ProviderData.(*providerMeta)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *providerMeta, got %T", req.ProviderData))
+		return
+	}
+
+	d.meta = meta
+}
@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
 )
 
 func TestProvider(t *testing.T) {
@@ -13,10 +20,51 @@ func TestProvider(t *testing.T) {
 	assert.NilError(t, err)
 }
 
-func testAccProviders(t *testing.T) map[string]func() (*schema.Provider, error) {
-	return map[string]func() (*schema.Provider, error){
-		"infra": func() (*schema.Provider, error) {
-			return New(), nil
+// TestNewServer checks the SDK and framework providers can be muxed, which requires their
+// provider schemas to be identical.
+func TestNewServer(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := NewServer(ctx)
+	assert.NilError(t, err)
+
+	resp, err := server().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(resp.Diagnostics), 0, "%v", resp.Diagnostics)
+
+	for _, name := range []string{"infra_access_keys", "infra_destination", "infra_destinations", "infra_groups", "infra_users"} {
+		assert.Assert(t, resp.DataSourceSchemas[name] != nil, "missing data source %s", name)
+	}
+
+	assert.Assert(t, resp.ResourceSchemas["infra_user"] != nil)
+}
+
+func TestNewProviderMeta_requestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	meta, err := newProviderMeta(providerConfig{
+		Host:           server.URL,
+		AccessKey:      "aaaaaaaaaa.bbbbbbbbbbbbbbbbbbbbbbbb",
+		RequestTimeout: 10 * time.Millisecond,
+	})
+	assert.NilError(t, err)
+
+	_, err = meta.client.GetUserSelf(context.Background())
+	assert.ErrorContains(t, err, "Client.Timeout exceeded")
+}
+
+func testAccProviders(t *testing.T) map[string]func() (tfprotov5.ProviderServer, error) {
+	return map[string]func() (tfprotov5.ProviderServer, error){
+		"infra": func() (tfprotov5.ProviderServer, error) {
+			server, err := NewServer(context.Background())
+			if err != nil {
+				return nil, err
+			}
+
+			return server(), nil
 		},
 	}
 }
@@ -27,3 +75,26 @@ func testAccPreCheck(t *testing.T) func() {
 		assert.Assert(t, accessKey != "", "`INFRA_ACCESS_KEY` must be set for acceptance tests")
 	}
 }
+
+// testProviderMeta returns provider metadata with a client for a mock Infra server
+// which serves requests with handler.
+func testProviderMeta(t *testing.T, handler http.Handler) *providerMeta {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &providerMeta{
+		client: &api.Client{
+			Name:      "terraform",
+			URL:       server.URL,
+			AccessKey: "aaaaaaaaaa.bbbbbbbbbbbbbbbbbbbbbbbb",
+			HTTP:      *server.Client(),
+		},
+	}
+}
+
+// testWriteJSON writes v as the JSON response body of a mock Infra server.
+func testWriteJSON(t *testing.T, w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	assert.NilError(t, json.NewEncoder(w).Encode(v))
+}
@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+// TestUpgradeResourceState loads resource states written by earlier provider versions
+// and checks they upgrade to the current schema. Fixtures are in testdata/state/<version>.
+func TestUpgradeResourceState(t *testing.T) {
+	type testCase struct {
+		fixture  string
+		typeName string
+		version  int64
+		expected map[string]any
+		// config, if set, is planned against the upgraded state, which must have no changes
+		config map[string]any
+	}
+
+	cases := []testCase{
+		{
+			fixture:  "0.17.1/infra_user.json",
+			typeName: "infra_user",
+			expected: map[string]any{
+				"id":                    "2Ygu6jvYUs",
+				"name":                  "alice@example.com",
+				"password":              "7Ns1Tp8bYQXwo4cfaVwq3kE8",
+				"deletion_policy":       "delete",
+				"allow_system_identity": false,
+				"password_wo":           nil,
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_group.json",
+			typeName: "infra_group",
+			expected: map[string]any{
+				"id":             "5tVp3GuoCZ",
+				"name":           "developers",
+				"adopt_existing": nil,
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_group_membership.json",
+			typeName: "infra_group_membership",
+			expected: map[string]any{
+				"id":       "2Ygu6jvYUs/5tVp3GuoCZ",
+				"user_id":  "2Ygu6jvYUs",
+				"group_id": "5tVp3GuoCZ",
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_grant_infra.json",
+			typeName: "infra_grant",
+			expected: map[string]any{
+				"id":                    "3LqyuG1M5F",
+				"user_id":               "2Ygu6jvYUs",
+				"infra":                 []any{map[string]any{"role": "admin"}},
+				"kubernetes":            []any{},
+				"ssh":                   []any{},
+				"destination":           []any{},
+				"allow_system_identity": nil,
+				"expired":               nil,
+			},
+			config: map[string]any{
+				"user_id": "2Ygu6jvYUs",
+				"infra":   []any{map[string]any{"role": "admin"}},
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_grant_kubernetes.json",
+			typeName: "infra_grant",
+			expected: map[string]any{
+				"id":                    "6hYtAxB3aR",
+				"group_id":              "5tVp3GuoCZ",
+				"infra":                 []any{},
+				"kubernetes":            []any{map[string]any{"cluster": "production", "namespace": "web", "role": "edit"}},
+				"allow_system_identity": nil,
+				"expired":               nil,
+			},
+			config: map[string]any{
+				"group_id":   "5tVp3GuoCZ",
+				"kubernetes": []any{map[string]any{"cluster": "production", "namespace": "web", "role": "edit"}},
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_identity_provider_oidc.json",
+			typeName: "infra_identity_provider",
+			expected: map[string]any{
+				"id":     "4C5s9fXzEd",
+				"issuer": "https://example.okta.com",
+				"azure":  []any{},
+				"google": []any{},
+				"okta":   []any{},
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_identity_provider_azure.json",
+			typeName: "infra_identity_provider",
+			expected: map[string]any{
+				"id":     "7Fh2KdPq9x",
+				"azure":  []any{map[string]any{"tenant_id": "0f9c1a2e-3b4d-4e5f-8a6b-7c8d9e0f1a2b"}},
+				"google": []any{},
+				"okta":   []any{},
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_identity_provider_google.json",
+			typeName: "infra_identity_provider",
+			expected: map[string]any{
+				"id":     "8Jk3LmNp4q",
+				"azure":  []any{},
+				"google": []any{map[string]any{"admin_email": "admin@example.com", "service_account_key": `{"type": "service_account"}`}},
+				"okta":   []any{},
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_identity_provider_okta.json",
+			typeName: "infra_identity_provider",
+			expected: map[string]any{
+				"id":     "9Ab4CdEf5g",
+				"azure":  []any{},
+				"google": []any{},
+				"okta":   []any{map[string]any{"issuer": "https://example.okta.com"}},
+			},
+		},
+		{
+			fixture:  "0.17.1/infra_access_key.json",
+			typeName: "infra_access_key",
+			expected: map[string]any{
+				"id":                 "2bYd7oVk4C",
+				"name":               "alice-2bYd7oVk4C",
+				"expires_in":         "8766h0m0s",
+				"inactivity_timeout": "72h0m0s",
+				"secret":             "2bYd7oVk4C.uQ3j6XsoJ8bwaK1tPLMfRz0H",
+			},
+		},
+	}
+
+	provider := New()
+	server := schema.NewGRPCProviderServer(provider)
+
+	for _, tc := range cases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata", "state", tc.fixture))
+			assert.NilError(t, err)
+
+			resp, err := server.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+				TypeName: tc.typeName,
+				Version:  tc.version,
+				RawState: &tfprotov5.RawState{JSON: raw},
+			})
+			assert.NilError(t, err)
+			assert.Equal(t, len(resp.Diagnostics), 0, "%v", resp.Diagnostics)
+
+			ty := provider.ResourcesMap[tc.typeName].CoreConfigSchema().ImpliedType()
+
+			value, err := msgpack.Unmarshal(resp.UpgradedState.MsgPack, ty)
+			assert.NilError(t, err)
+
+			b, err := ctyjson.Marshal(value, ty)
+			assert.NilError(t, err)
+
+			var state map[string]any
+			assert.NilError(t, json.Unmarshal(b, &state))
+
+			actual := make(map[string]any, len(tc.expected))
+			for key := range tc.expected {
+				actual[key] = state[key]
+			}
+
+			assert.DeepEqual(t, actual, tc.expected)
+
+			if tc.config != nil {
+				r := provider.ResourcesMap[tc.typeName]
+				meta := testProviderMeta(t, testUpgradeServer(t, state))
+
+				instanceState, err := r.ShimInstanceStateFromValue(value)
+				assert.NilError(t, err)
+
+				instanceState, diags := r.RefreshWithoutUpgrade(context.Background(), instanceState, meta)
+				assert.Assert(t, !diags.HasError(), "%v", diags)
+
+				diff, err := r.Diff(context.Background(), instanceState, terraform.NewResourceConfigRaw(tc.config), meta)
+				assert.NilError(t, err)
+				assert.Assert(t, diff.Empty(), "unexpected changes: %v", diff)
+			}
+		})
+	}
+}
+
+// testUpgradeServer returns a mock Infra server which serves the grant, user and group in
+// an upgraded infra_grant state, so the state can be refreshed.
+func testUpgradeServer(t *testing.T, state map[string]any) http.Handler {
+	t.Helper()
+
+	id := func(key string) uid.ID {
+		s, _ := state[key].(string)
+		if s == "" {
+			return 0
+		}
+
+		id, err := uid.Parse([]byte(s))
+		assert.NilError(t, err)
+		return id
+	}
+
+	grant := api.Grant{ID: id("id"), User: id("user_id"), Group: id("group_id")}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/grants/"+grant.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, grant)
+	})
+	mux.HandleFunc("/api/users/", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, api.User{ID: grant.User, Name: "alice@example.com"})
+	})
+	mux.HandleFunc("/api/groups/", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, api.Group{ID: grant.Group, Name: "developers"})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	})
+
+	return mux
+}
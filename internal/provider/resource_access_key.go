@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -11,6 +12,12 @@ import (
 	"github.com/infrahq/infra/api"
 )
 
+var accessKeyAPIFields = apiFieldPaths{
+	"name":              cty.GetAttrPath("name"),
+	"expiry":            cty.GetAttrPath("expires_in"),
+	"inactivityTimeout": cty.GetAttrPath("inactivity_timeout"),
+}
+
 func resourceAccessKey() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides an Infra access key. This resource can be used to create and manage connector access keys.
@@ -21,6 +28,12 @@ func resourceAccessKey() *schema.Resource {
 		ReadContext:   resourceAccessKeyRead,
 		DeleteContext: resourceAccessKeyDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Description: "The access key's unique identifier.",
@@ -78,10 +91,10 @@ func resourceAccessKey() *schema.Resource {
 }
 
 func resourceAccessKeyCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	if err := requireMinimumServerVersion(ctx, client, "0.20.0"); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, accessKeyAPIFields)
 	}
 
 	var diags diag.Diagnostics
@@ -90,17 +103,21 @@ func resourceAccessKeyCreate(ctx context.Context, d *schema.ResourceData, m any)
 
 	user, err = userFromEmail(ctx, client, "connector")
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{Summary: err.Error()})
+		diags = append(diags, apiErrorDiagnostics(err, nil)...)
 	}
 
 	expires, err := ParseDuration(d, "expires_in", "expires_at", "8766h0m0s")
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{Summary: err.Error()})
+		diags = append(diags, diag.Diagnostic{Severity: diag.Error, Summary: err.Error()})
 	}
 
 	inactivity, err := ParseDuration(d, "inactivity_timeout", "", expires.String())
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{Summary: err.Error()})
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: cty.GetAttrPath("inactivity_timeout"),
+		})
 	}
 
 	if inactivity > expires {
@@ -120,7 +137,7 @@ func resourceAccessKeyCreate(ctx context.Context, d *schema.ResourceData, m any)
 
 	response, err := client.CreateAccessKey(ctx, request)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, accessKeyAPIFields)
 	}
 
 	if err := d.Set("name", response.Name); err != nil {
@@ -140,7 +157,7 @@ func resourceAccessKeyCreate(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceAccessKeyRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	request := api.ListAccessKeysRequest{
 		Name:        d.Get("name").(string),
@@ -152,7 +169,7 @@ func resourceAccessKeyRead(ctx context.Context, d *schema.ResourceData, m any) d
 
 	response, err := client.ListAccessKeys(ctx, request)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, accessKeyAPIFields)
 	}
 
 	if response.Count < 1 {
@@ -170,7 +187,7 @@ func resourceAccessKeyRead(ctx context.Context, d *schema.ResourceData, m any) d
 }
 
 func resourceAccessKeyDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -178,7 +195,7 @@ func resourceAccessKeyDelete(ctx context.Context, d *schema.ResourceData, m any)
 	}
 
 	if err := client.DeleteAccessKey(ctx, id); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, accessKeyAPIFields)
 	}
 
 	d.SetId("")
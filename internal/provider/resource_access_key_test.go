@@ -16,8 +16,8 @@ func TestAccResourceAccessKey(t *testing.T) {
 	resourceName := fmt.Sprintf("infra_access_key.%s", t.Name())
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceAccessKey_connector(t),
@@ -3,22 +3,52 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
 )
 
 func resourceGrant() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides an Infra grant. This resource can be used to assign grants to users or groups.",
+		Description: `Provides an Infra grant. This resource can be used to assign grants to users or groups.
+
+An existing grant can be imported by its ID. An imported grant is described by an ` + "`infra`" + ` block for Infra roles and by a ` + "`destination`" + ` block otherwise; configuring it with a ` + "`kubernetes`" + ` or ` + "`ssh`" + ` block replaces it with an identical grant.
+
+~> Removing the last Infra admin grant is refused when the grant is destroyed, which is checked during apply rather than in the plan. Set ` + "`lifecycle { prevent_destroy = true }`" + ` on grants which must not be destroyed to stop such plans.`,
 
 		CreateContext: resourceGrantCreate,
 		ReadContext:   resourceGrantRead,
+		UpdateContext: resourceGrantUpdate,
 		DeleteContext: resourceGrantDelete,
 
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGrantImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		CustomizeDiff: customdiff.All(
+			forceNewIfBlocksChange("infra", "kubernetes", "ssh", "destination"),
+			resourceGrantExpiryCustomizeDiff,
+			resourceGrantSystemIdentityCustomizeDiff,
+			resourceGrantLastAdminCustomizeDiff,
+			resourceGrantTargetsCustomizeDiff,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Description: "The grant's unique identifier.",
@@ -72,10 +102,9 @@ func resourceGrant() *schema.Resource {
 				Description: "Infra grant configurations.",
 				Type:        schema.TypeList,
 				Optional:    true,
-				ForceNew:    true,
 				MaxItems:    1,
 				ExactlyOneOf: []string{
-					"infra", "kubernetes",
+					"infra", "kubernetes", "ssh", "destination",
 				},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -83,7 +112,6 @@ func resourceGrant() *schema.Resource {
 							Description: "The name of the Infra role to assign to the user. Valid roles are `admin` or `view`.",
 							Type:        schema.TypeString,
 							Required:    true,
-							ForceNew:    true,
 							ValidateDiagFunc: validation.ToDiagFunc(
 								validation.StringInSlice([]string{"admin", "view"}, true),
 							),
@@ -95,10 +123,9 @@ func resourceGrant() *schema.Resource {
 				Description: "Kubernetes grant configurations.",
 				Type:        schema.TypeList,
 				Optional:    true,
-				ForceNew:    true,
 				MaxItems:    1,
 				ExactlyOneOf: []string{
-					"infra", "kubernetes",
+					"infra", "kubernetes", "ssh", "destination",
 				},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -106,7 +133,6 @@ func resourceGrant() *schema.Resource {
 							Description: "The name of the Kubernetes ClusterRole to assign to the user. See [Kubernetes Roles](https://infrahq.com/docs/integrations/kubernetes#roles) for a list of valid roles.",
 							Type:        schema.TypeString,
 							Required:    true,
-							ForceNew:    true,
 						},
 						"cluster": {
 							Description: "The name of the Kubernetes cluster to assign to the user.",
@@ -123,52 +149,132 @@ func resourceGrant() *schema.Resource {
 					},
 				},
 			},
+			"ssh": {
+				Description: "SSH grant configurations.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				ExactlyOneOf: []string{
+					"infra", "kubernetes", "ssh", "destination",
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Description: "The name of the SSH destination to assign to the user.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"role": {
+							Description: "The name of the role to assign to the user. Default is `connect`.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"destination": {
+				Description: "Grant configurations for any kind of destination.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				ExactlyOneOf: []string{
+					"infra", "kubernetes", "ssh", "destination",
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The name of the destination to assign to the user.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"resource": {
+							Description: "The name of the resource within the destination to assign to the user, e.g. a Kubernetes namespace.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+						},
+						"role": {
+							Description: "The name of the role to assign to the user. Valid roles depend on the kind of destination.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+					},
+				},
+			},
+			"expires_in": {
+				Description:      `The amount of time before the grant expires. Format is a duration string, a sequence of decimal numbers, each with optional fraction and a unit suffix, such as "300s" or "2h45m". Valid time units are "s", "m", "h". If omitted, the grant does not expire.`,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateStringIsDuration(),
+				DiffSuppressFunc: DurationDiffSuppressFunc(),
+				ConflictsWith: []string{
+					"expires_at",
+				},
+			},
+			"expires_at": {
+				Description:      `The date-time when the grant will expire. Format is a RFC3339 timestamp, e.g. "2006-01-02T15:04:05Z07:00." If omitted, the grant does not expire.`,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+				ConflictsWith: []string{
+					"expires_in",
+				},
+			},
+			"allow_system_identity": {
+				Description: "Allow this grant to be assigned to a system identity, such as the `connector` user.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"adopted": {
+				Description: "Whether the grant already existed in Infra when this resource created it, e.g. because another `infra_grant` resource manages the same grant. An adopted grant is left in Infra when this resource is destroyed.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"expired": {
+				Description: "Whether the grant has expired. Expiry is enforced by the provider: once `expires_at` has passed, the next apply removes the grant from Infra and the refresh after it removes the grant from the state. Remove an expired grant from the configuration, otherwise it is planned to be created again.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
 		},
 	}
 }
 
+// resourceGrantCreate creates a grant. Infra does not create duplicate grants: if an
+// identical grant exists, it is returned with `wasCreated` false instead of an error, and
+// this resource adopts the existing grant.
 func resourceGrantCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	meta := m.(*providerMeta)
+	client := meta.client
 
-	request := &api.GrantRequest{}
-
-	if d.Get("user_id").(string) != "" || d.Get("user_name").(string) != "" {
-		user, err := userFromIDOrEmail(ctx, client, d, "user_id", "user_name")
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		request.User = user.ID
+	request, err := grantRequestFromResourceData(ctx, client, d)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
 	}
 
-	if d.Get("group_id").(string) != "" || d.Get("group_name").(string) != "" {
-		group, err := groupFromIDOrName(ctx, client, d, "group_id", "group_name")
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		request.Group = group.ID
+	if err := setGrantExpiresAt(d); err != nil {
+		return diag.FromErr(err)
 	}
 
-	for i := range d.Get("infra").([]interface{}) {
-		request.Resource = "infra"
-		request.Privilege = d.Get(fmt.Sprintf("infra.%d.role", i)).(string)
-		break
+	if err := d.Set("expired", false); err != nil {
+		return diag.FromErr(err)
 	}
 
-	for i := range d.Get("kubernetes").([]interface{}) {
-		resource := d.Get(fmt.Sprintf("kubernetes.%d.cluster", i)).(string)
-		if namespace := d.Get(fmt.Sprintf("kubernetes.%d.namespace", i)).(string); namespace != "" {
-			resource = fmt.Sprintf("%s.%s", resource, namespace)
-		}
+	response, err := client.CreateGrant(ctx, request)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
 
-		request.Resource = resource
-		request.Privilege = d.Get(fmt.Sprintf("kubernetes.%d.role", i)).(string)
-		break
+	// Infra returns the existing grant instead of creating an identical one. It may belong
+	// to an instance which is being replaced, which must not delete it.
+	if !response.WasCreated {
+		meta.sharedGrants.add(response.ID)
 	}
 
-	response, err := client.CreateGrant(ctx, request)
-	if err != nil {
+	if err := d.Set("adopted", !response.WasCreated); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -177,7 +283,7 @@ func resourceGrantCreate(ctx context.Context, d *schema.ResourceData, m any) dia
 }
 
 func resourceGrantRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -186,13 +292,33 @@ func resourceGrantRead(ctx context.Context, d *schema.ResourceData, m any) diag.
 
 	grant, err := client.GetGrant(ctx, id)
 	if err != nil {
+		// the grant was deleted outside of terraform, or by the apply after it expired
+		if api.ErrorStatusCode(err) == http.StatusNotFound {
+			d.SetId("")
+
+			var diags diag.Diagnostics
+			return diags
+		}
+
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	// state written before allow_system_identity was added has no value, which would
+	// otherwise be planned as a change to its default
+	if err := d.Set("allow_system_identity", d.Get("allow_system_identity").(bool)); err != nil {
 		return diag.FromErr(err)
 	}
 
+	var diags diag.Diagnostics
+
 	if grant.User != 0 {
 		user, err := client.GetUser(ctx, grant.User)
 		if err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, nil)
+		}
+
+		if isSystemIdentity(user) && !d.Get("allow_system_identity").(bool) {
+			diags = append(diags, systemIdentityWarning(user))
 		}
 
 		if err := d.Set("user_id", user.ID.String()); err != nil {
@@ -207,7 +333,7 @@ func resourceGrantRead(ctx context.Context, d *schema.ResourceData, m any) diag.
 	if grant.Group != 0 {
 		group, err := client.GetGroup(ctx, grant.Group)
 		if err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, nil)
 		}
 
 		if err := d.Set("group_id", group.ID.String()); err != nil {
@@ -219,24 +345,461 @@ func resourceGrantRead(ctx context.Context, d *schema.ResourceData, m any) diag.
 		}
 	}
 
-	var diags diag.Diagnostics
 	return diags
 }
 
-func resourceGrantDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+// resourceGrantImport sets the grant configuration of an existing grant, which is not
+// read by resourceGrantRead. Other kinds of grants are imported as a destination grant.
+func resourceGrantImport(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	client := m.(*providerMeta).client
+
+	id, err := uid.Parse([]byte(d.Id()))
+	if err != nil {
+		return nil, err
+	}
+
+	grant, err := client.GetGrant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if grant.Resource == "infra" {
+		if err := d.Set("infra", []interface{}{
+			map[string]interface{}{"role": grant.Privilege},
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		name, resource, _ := strings.Cut(grant.Resource, ".")
+		if err := d.Set("destination", []interface{}{
+			map[string]interface{}{"name": name, "resource": resource, "role": grant.Privilege},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range []string{"allow_system_identity", "adopted", "expired"} {
+		if err := d.Set(key, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceGrantUpdate changes the role of a grant. Grants cannot be modified so a new
+// grant is created before the old grant is deleted. This ensures the user or group
+// never loses access while the change is applied.
+func resourceGrantUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err := client.DeleteGrant(ctx, id); err != nil {
+	// the grant is removed from the state by the next refresh, since an update cannot
+	// remove it from the state
+	if d.Get("expired").(bool) {
+		if isInfraAdminGrant(d.Get("infra")) {
+			if err := checkNotLastInfraAdminGrant(ctx, client, id); err != nil {
+				return apiErrorDiagnostics(err, nil)
+			}
+		}
+
+		if err := client.DeleteGrant(ctx, id); err != nil && api.ErrorStatusCode(err) != http.StatusNotFound {
+			return apiErrorDiagnostics(err, nil)
+		}
+
+		var diags diag.Diagnostics
+		return diags
+	}
+
+	// the grant in Infra does not change with its expiry
+	if d.HasChange("expires_in") {
+		if err := setGrantExpiresAt(d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	request, err := grantRequestFromResourceData(ctx, client, d)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	response, err := client.CreateGrant(ctx, request)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	d.SetId(response.ID.String())
+
+	if response.ID != id {
+		// the old grant is left in Infra if it was adopted
+		adopted := d.Get("adopted").(bool)
+
+		if err := d.Set("adopted", !response.WasCreated); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if !adopted {
+			if err := client.DeleteGrant(ctx, id); err != nil && api.ErrorStatusCode(err) != http.StatusNotFound {
+				return apiErrorDiagnostics(err, nil)
+			}
+		}
+	}
+
+	return resourceGrantRead(ctx, d, m)
+}
+
+// resourceGrantExpiryCustomizeDiff plans the removal of a grant once it has expired. The
+// API does not support grant expiry so it is enforced by the provider.
+func resourceGrantExpiryCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m any) error {
+	// a changed expires_in sets expires_at again when the grant is updated
+	oldExpiresIn, newExpiresIn := d.GetChange("expires_in")
+	expiresInChanged := oldExpiresIn != newExpiresIn &&
+		!DurationDiffSuppressFunc()("expires_in", oldExpiresIn.(string), newExpiresIn.(string), nil)
+
+	if d.Id() != "" && expiresInChanged && !configured(d.GetRawConfig(), "expires_at") {
+		if err := d.SetNewComputed("expires_at"); err != nil {
+			return err
+		}
+	}
+
+	if !d.NewValueKnown("expires_at") {
+		return nil
+	}
+
+	expired, err := grantExpired(d.Get("expires_at").(string), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if d.Id() == "" {
+		if expired {
+			return fmt.Errorf("expires_at must be in the future: %s. Remove an expired grant from the configuration instead of creating it again", d.Get("expires_at"))
+		}
+
+		return nil
+	}
+
+	if expired && !d.Get("expired").(bool) {
+		return d.SetNew("expired", true)
+	}
+
+	return nil
+}
+
+// setGrantExpiresAt sets expires_at from expires_in. If neither is set, the grant does not
+// expire.
+func setGrantExpiresAt(d *schema.ResourceData) error {
+	if s := d.Get("expires_in").(string); s != "" {
+		expires, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		return d.Set("expires_at", time.Now().Add(expires).UTC().Format(time.RFC3339))
+	}
+
+	if configured(d.GetRawConfig(), "expires_at") {
+		return nil
+	}
+
+	return d.Set("expires_at", "")
+}
+
+// grantExpired returns true if expiresAt is set and is not after now.
+func grantExpired(expiresAt string, now time.Time) (bool, error) {
+	if expiresAt == "" {
+		return false, nil
+	}
+
+	expires, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	return !expires.After(now), nil
+}
+
+// resourceGrantSystemIdentityCustomizeDiff refuses to create or change a grant for a
+// system identity unless `allow_system_identity` is set.
+func resourceGrantSystemIdentityCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m any) error {
+	if d.Get("allow_system_identity").(bool) || !d.NewValueKnown("user_id") {
+		return nil
+	}
+
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+
+	s := d.Get("user_id").(string)
+	if s == "" {
+		return nil
+	}
+
+	userID, err := uid.Parse([]byte(s))
+	if err != nil {
+		return err
+	}
+
+	user, err := m.(*providerMeta).client.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if isSystemIdentity(user) {
+		return fmt.Errorf("refusing to grant access to system identity %s: set `allow_system_identity = true` to override", user.Name)
+	}
+
+	return nil
+}
+
+// resourceGrantLastAdminCustomizeDiff refuses to change the role of the last Infra admin
+// grant, or to remove it once it has expired. Destroying the grant is checked when it is
+// deleted.
+func resourceGrantLastAdminCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m any) error {
+	if d.Id() == "" || !d.HasChanges("infra", "expired") {
+		return nil
+	}
+
+	oldInfra, newInfra := d.GetChange("infra")
+	if !isInfraAdminGrant(oldInfra) {
+		return nil
+	}
+
+	if isInfraAdminGrant(newInfra) && !d.Get("expired").(bool) {
+		return nil
+	}
+
+	id, err := uid.Parse([]byte(d.Id()))
+	if err != nil {
+		return err
+	}
+
+	return checkNotLastInfraAdminGrant(ctx, m.(*providerMeta).client, id)
+}
+
+// isInfraAdminGrant returns true if the infra configuration assigns the admin role.
+func isInfraAdminGrant(infra any) bool {
+	return strings.EqualFold(blockValues(infra)["role"], "admin")
+}
+
+// checkNotLastInfraAdminGrant returns an error if the grant is the only Infra admin grant.
+// Removing it would leave no one able to manage Infra.
+func checkNotLastInfraAdminGrant(ctx context.Context, client *api.Client, id uid.ID) error {
+	grants, err := listInfraAdminGrants(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if isLastGrant(grants, id) {
+		return errLastInfraAdminGrant
+	}
+
+	return nil
+}
+
+var errLastInfraAdminGrant = fmt.Errorf("refusing to remove the last Infra admin grant: grant the `admin` role on `infra` to another user or group first")
+
+func listInfraAdminGrants(ctx context.Context, client *api.Client) ([]api.Grant, error) {
+	return listAll(func(page int) (*api.ListResponse[api.Grant], error) {
+		return client.ListGrants(ctx, api.ListGrantsRequest{
+			Resource:  "infra",
+			Privilege: "admin",
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+}
+
+// isLastGrant returns true if id is the only grant in grants.
+func isLastGrant(grants []api.Grant, id uid.ID) bool {
+	return len(grants) == 1 && grants[0].ID == id
+}
+
+// resourceGrantTargetsCustomizeDiff checks the Kubernetes cluster, namespace and role of the
+// grant against the destinations when validate_grant_targets is set. Unknown targets are
+// errors rather than warnings because CustomizeDiff cannot return warnings, which is why
+// the validation is opt-in.
+func resourceGrantTargetsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m any) error {
+	meta := m.(*providerMeta)
+	if !meta.validateGrantTargets {
+		return nil
+	}
+
+	kubernetes := blockValues(d.Get("kubernetes"))
+	if len(kubernetes) == 0 {
+		return nil
+	}
+
+	// values which are not known until apply cannot be validated
+	for _, key := range []string{"cluster", "namespace", "role"} {
+		if !d.NewValueKnown("kubernetes.0." + key) {
+			return nil
+		}
+	}
+
+	destination, err := destinationFromName(ctx, meta.client, kubernetes["cluster"])
+	if err != nil {
+		return err
+	}
+
+	namespace, role := kubernetes["namespace"], kubernetes["role"]
+
+	return validateKubernetesGrantTarget(destination, namespace, role)
+}
+
+// validateKubernetesGrantTarget checks the namespace and role against the resources and
+// roles reported by the destination's connector. Connectors which have not reported any
+// resources or roles are not validated.
+func validateKubernetesGrantTarget(destination *api.Destination, namespace, role string) error {
+	if destination.Kind != "" && destination.Kind != "kubernetes" {
+		return fmt.Errorf("destination %s is not a kubernetes cluster: %s", destination.Name, destination.Kind)
+	}
+
+	if namespace != "" && len(destination.Resources) > 0 && !containsString(destination.Resources, namespace) {
+		return fmt.Errorf("namespace not found in destination %s: %s", destination.Name, namespace)
+	}
+
+	if len(destination.Roles) > 0 && !containsString(destination.Roles, role) {
+		return fmt.Errorf("role not found in destination %s: %s. valid roles are %s", destination.Name, role, strings.Join(destination.Roles, ", "))
+	}
+
+	return nil
+}
+
+func resourceGrantDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	meta := m.(*providerMeta)
+	client := meta.client
+
+	id, err := ParseID(d, "id")
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// the grant is now managed by the instance which replaced this one
+	if meta.sharedGrants.remove(id) {
+		d.SetId("")
+		return grantLeftInInfraWarning(id, "The infra_grant resource which replaced this one adopted the grant and now manages it. Since it is adopted, it is also left in Infra when that resource is destroyed.")
+	}
+
+	if d.Get("adopted").(bool) {
+		d.SetId("")
+		return grantLeftInInfraWarning(id, "The grant already existed when this resource created it, e.g. because another infra_grant resource manages it.")
+	}
+
+	if isInfraAdminGrant(d.Get("infra")) {
+		if err := checkNotLastInfraAdminGrant(ctx, client, id); err != nil {
+			return apiErrorDiagnostics(err, nil)
+		}
+	}
+
+	// the grant may have already been removed outside of Terraform
+	if err := client.DeleteGrant(ctx, id); err != nil && api.ErrorStatusCode(err) != http.StatusNotFound {
+		return apiErrorDiagnostics(err, nil)
+	}
+
 	d.SetId("")
 
 	var diags diag.Diagnostics
 	return diags
 }
+
+func grantLeftInInfraWarning(id uid.ID, reason string) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Grant left in Infra",
+			Detail:   fmt.Sprintf("Grant %s was removed from the state but not deleted. %s Delete it in Infra if it is no longer needed.", id, reason),
+		},
+	}
+}
+
+func grantRequestFromResourceData(ctx context.Context, client *api.Client, d *schema.ResourceData) (*api.GrantRequest, error) {
+	request := &api.GrantRequest{}
+
+	if d.Get("user_id").(string) != "" || d.Get("user_name").(string) != "" {
+		user, err := userFromIDOrEmail(ctx, client, d, "user_id", "user_name")
+		if err != nil {
+			return nil, err
+		}
+
+		request.User = user.ID
+	}
+
+	if d.Get("group_id").(string) != "" || d.Get("group_name").(string) != "" {
+		group, err := groupFromIDOrName(ctx, client, d, "group_id", "group_name")
+		if err != nil {
+			return nil, err
+		}
+
+		request.Group = group.ID
+	}
+
+	if infra := blockValues(d.Get("infra")); len(infra) > 0 {
+		request.Resource = "infra"
+		request.Privilege = infra["role"]
+	}
+
+	if kubernetes := blockValues(d.Get("kubernetes")); len(kubernetes) > 0 {
+		request.Resource = kubernetes["cluster"]
+		if namespace := kubernetes["namespace"]; namespace != "" {
+			request.Resource = fmt.Sprintf("%s.%s", request.Resource, namespace)
+		}
+
+		request.Privilege = kubernetes["role"]
+	}
+
+	if ssh := blockValues(d.Get("ssh")); len(ssh) > 0 {
+		request.Resource = ssh["host"]
+		request.Privilege = ssh["role"]
+		if request.Privilege == "" {
+			request.Privilege = "connect"
+		}
+	}
+
+	if destination := blockValues(d.Get("destination")); len(destination) > 0 {
+		request.Resource = destination["name"]
+		if subresource := destination["resource"]; subresource != "" {
+			request.Resource = fmt.Sprintf("%s.%s", request.Resource, subresource)
+		}
+
+		request.Privilege = destination["role"]
+	}
+
+	return request, nil
+}
+
+// grantSet is a set of grant IDs which is safe for concurrent use.
+type grantSet struct {
+	mu  sync.Mutex
+	ids map[uid.ID]bool
+}
+
+func (s *grantSet) add(id uid.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ids == nil {
+		s.ids = make(map[uid.ID]bool)
+	}
+
+	s.ids[id] = true
+}
+
+// remove removes id from the set and returns true if it was in the set.
+func (s *grantSet) remove(id uid.ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ids[id] {
+		return false
+	}
+
+	delete(s.ids, id)
+	return true
+}
@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
 
+	"github.com/infrahq/infra/api"
 	"github.com/infrahq/infra/uid"
 )
 
@@ -20,8 +27,8 @@ func TestAccResourceGrant_userKubernetes(t *testing.T) {
 	resourceName := "infra_grant.test"
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceGrant_userKubernetes(email, "admin", cluster),
@@ -49,6 +56,11 @@ func TestAccResourceGrant_userKubernetes(t *testing.T) {
 					testAccCheckIDChanged(&id2, &id3),
 				),
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 			{
 				Config: testAccResourceGrant_userKubernetesWithNamespace(email, "cluster-admin", cluster, namespace),
 				Check: resource.ComposeTestCheckFunc(
@@ -125,8 +137,8 @@ func TestAccResourceGrant_userInfra(t *testing.T) {
 	resourceName := "infra_grant.test"
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceGrant_userInfra(email, "admin", cluster),
@@ -203,8 +215,8 @@ func TestAccResourceGrant_groupKubernetes(t *testing.T) {
 	resourceName := "infra_grant.test"
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceGrant_groupKubernetes(name, "admin", cluster),
@@ -308,8 +320,8 @@ func TestAccResourceGrant_groupInfra(t *testing.T) {
 	resourceName := "infra_grant.test"
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceGrant_groupInfra(name, "admin", cluster),
@@ -374,3 +386,656 @@ resource "infra_grant" "test" {
 	]
 }`, name, role, cluster)
 }
+
+func TestAccResourceGrant_userSSH(t *testing.T) {
+	var id1, id2 uid.ID
+
+	email := randomEmail()
+
+	host := randomName("host")
+
+	resourceName := "infra_grant.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrant_userSSH(email, host),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id1)),
+					resource.TestCheckResourceAttr(resourceName, "user_name", email),
+					resource.TestCheckResourceAttr(resourceName, "ssh.0.host", host),
+					resource.TestCheckResourceAttr(resourceName, "ssh.0.role", "connect"),
+				),
+			},
+			{
+				Config: testAccResourceGrant_userDestination(email, "connect", host, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id2)),
+					resource.TestCheckResourceAttr(resourceName, "user_name", email),
+					resource.TestCheckResourceAttr(resourceName, "destination.0.name", host),
+					resource.TestCheckResourceAttr(resourceName, "destination.0.role", "connect"),
+					testAccCheckIDChanged(&id1, &id2),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceGrant_userDestination(t *testing.T) {
+	var id1, id2 uid.ID
+
+	email := randomEmail()
+
+	cluster := randomName("cluster")
+	namespace := randomName("ns")
+
+	resourceName := "infra_grant.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrant_userDestination(email, "view", cluster, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id1)),
+					resource.TestCheckResourceAttr(resourceName, "user_name", email),
+					resource.TestCheckResourceAttr(resourceName, "destination.0.name", cluster),
+					resource.TestCheckResourceAttr(resourceName, "destination.0.role", "view"),
+				),
+			},
+			{
+				Config: testAccResourceGrant_userDestination(email, "view", cluster, namespace),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id2)),
+					resource.TestCheckResourceAttr(resourceName, "user_name", email),
+					resource.TestCheckResourceAttr(resourceName, "destination.0.resource", namespace),
+					testAccCheckIDChanged(&id1, &id2),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceGrant_userSSH(email, host string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "test" {
+	name = "%[1]s"
+}
+
+resource "infra_grant" "test" {
+	user_id = infra_user.test.id
+
+	ssh {
+		host = "%[2]s"
+	}
+}`, email, host)
+}
+
+func testAccResourceGrant_userDestination(email, role, name, subresource string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "test" {
+	name = "%[1]s"
+}
+
+resource "infra_grant" "test" {
+	user_id = infra_user.test.id
+
+	destination {
+		name = "%[3]s"
+		resource = "%[4]s"
+		role = "%[2]s"
+	}
+}`, email, role, name, subresource)
+}
+
+func TestValidateKubernetesGrantTarget(t *testing.T) {
+	destination := &api.Destination{
+		Name:      "cluster",
+		Kind:      "kubernetes",
+		Resources: []string{"default", "kube-system"},
+		Roles:     []string{"cluster-admin", "edit", "view"},
+	}
+
+	cases := map[string]struct {
+		destination *api.Destination
+		namespace   string
+		role        string
+		expected    string
+	}{
+		"cluster": {
+			destination: destination,
+			role:        "view",
+		},
+		"namespace": {
+			destination: destination,
+			namespace:   "default",
+			role:        "edit",
+		},
+		"unknown namespace": {
+			destination: destination,
+			namespace:   "defualt",
+			role:        "edit",
+			expected:    "namespace not found in destination cluster: defualt",
+		},
+		"unknown role": {
+			destination: destination,
+			role:        "viewer",
+			expected:    "role not found in destination cluster: viewer. valid roles are cluster-admin, edit, view",
+		},
+		"not kubernetes": {
+			destination: &api.Destination{Name: "host", Kind: "ssh"},
+			role:        "view",
+			expected:    "destination host is not a kubernetes cluster: ssh",
+		},
+		"not reported": {
+			destination: &api.Destination{Name: "cluster", Kind: "kubernetes"},
+			namespace:   "default",
+			role:        "view",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateKubernetesGrantTarget(tc.destination, tc.namespace, tc.role)
+			if tc.expected == "" {
+				assert.NilError(t, err)
+				return
+			}
+
+			assert.Error(t, err, tc.expected)
+		})
+	}
+}
+
+func TestAccResourceGrant_userExpires(t *testing.T) {
+	email := randomEmail()
+
+	cluster := randomName("cluster")
+
+	resourceName := "infra_grant.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrant_userExpires(email, cluster, "1h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "expires_in", "1h"),
+					resource.TestCheckResourceAttrSet(resourceName, "expires_at"),
+					resource.TestCheckResourceAttr(resourceName, "expired", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceGrant_userExpires(email, cluster, expiresIn string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "test" {
+	name = "%[1]s"
+}
+
+resource "infra_grant" "test" {
+	user_id = infra_user.test.id
+	expires_in = "%[3]s"
+
+	kubernetes {
+		role = "view"
+		cluster = "%[2]s"
+	}
+}`, email, cluster, expiresIn)
+}
+
+func TestGrantExpired(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		expiresAt string
+		expected  bool
+	}{
+		"never": {
+			expiresAt: "",
+		},
+		"future": {
+			expiresAt: "2022-10-01T13:00:00Z",
+		},
+		"now": {
+			expiresAt: "2022-10-01T12:00:00Z",
+			expected:  true,
+		},
+		"past": {
+			expiresAt: "2022-10-01T07:00:00-05:00",
+			expected:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expired, err := grantExpired(tc.expiresAt, now)
+			assert.NilError(t, err)
+			assert.Equal(t, expired, tc.expected)
+		})
+	}
+}
+
+func TestIsLastGrant(t *testing.T) {
+	grants := []api.Grant{{ID: 2}, {ID: 3}}
+
+	assert.Equal(t, isLastGrant(grants, 2), false)
+	assert.Equal(t, isLastGrant(grants[:1], 2), true)
+	assert.Equal(t, isLastGrant(grants[:1], 3), false)
+	assert.Equal(t, isLastGrant(nil, 2), false)
+}
+
+func TestIsInfraAdminGrant(t *testing.T) {
+	cases := map[string]struct {
+		infra    any
+		expected bool
+	}{
+		"empty": {
+			infra: []interface{}{},
+		},
+		"admin": {
+			infra:    []interface{}{map[string]interface{}{"role": "admin"}},
+			expected: true,
+		},
+		"Admin": {
+			infra:    []interface{}{map[string]interface{}{"role": "Admin"}},
+			expected: true,
+		},
+		"view": {
+			infra: []interface{}{map[string]interface{}{"role": "view"}},
+		},
+		"nil": {
+			infra: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, isInfraAdminGrant(tc.infra), tc.expected)
+		})
+	}
+}
+
+func TestResourceGrantRead_notFound(t *testing.T) {
+	id := uid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/grants/"+id.String(), func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusNotFound, api.Error{Code: http.StatusNotFound, Message: "not found"})
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceGrant().Schema, map[string]interface{}{})
+	d.SetId(id.String())
+
+	diags := resourceGrantRead(context.Background(), d, testProviderMeta(t, mux))
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+	assert.Equal(t, d.Id(), "")
+}
+
+// TestResourceGrant_import imports existing grants, which are then managed by the
+// configuration generated for them without changes.
+func TestResourceGrant_import(t *testing.T) {
+	user := api.User{ID: uid.New(), Name: "alice@example.com"}
+
+	cases := map[string]struct {
+		grant  api.Grant
+		config map[string]interface{}
+	}{
+		"infra": {
+			grant: api.Grant{ID: uid.New(), User: user.ID, Privilege: "admin", Resource: "infra"},
+			config: map[string]interface{}{
+				"user_id": user.ID.String(),
+				"infra":   []interface{}{map[string]interface{}{"role": "admin"}},
+			},
+		},
+		"destination": {
+			grant: api.Grant{ID: uid.New(), User: user.ID, Privilege: "connect", Resource: "bastion"},
+			config: map[string]interface{}{
+				"user_id":     user.ID.String(),
+				"destination": []interface{}{map[string]interface{}{"name": "bastion", "role": "connect"}},
+			},
+		},
+		"destination resource": {
+			grant: api.Grant{ID: uid.New(), User: user.ID, Privilege: "edit", Resource: "production.default"},
+			config: map[string]interface{}{
+				"user_id": user.ID.String(),
+				"destination": []interface{}{
+					map[string]interface{}{"name": "production", "resource": "default", "role": "edit"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/users/"+user.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+				testWriteJSON(t, w, http.StatusOK, user)
+			})
+			mux.HandleFunc("/api/grants/"+tc.grant.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodGet)
+				testWriteJSON(t, w, http.StatusOK, tc.grant)
+			})
+
+			meta := testProviderMeta(t, mux)
+			ctx := context.Background()
+
+			r := resourceGrant()
+			d := r.Data(nil)
+			d.SetId(tc.grant.ID.String())
+
+			imported, err := r.Importer.StateContext(ctx, d, meta)
+			assert.NilError(t, err)
+			assert.Equal(t, len(imported), 1)
+
+			state, diags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+			assert.Assert(t, !diags.HasError(), "%v", diags)
+			assert.Equal(t, state.Attributes["adopted"], "false")
+
+			diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(tc.config), meta)
+			assert.NilError(t, err)
+			assert.Assert(t, diff.Empty(), "unexpected changes: %v", diff)
+		})
+	}
+
+	t.Run("invalid ID", func(t *testing.T) {
+		r := resourceGrant()
+		d := r.Data(nil)
+		d.SetId("not an ID")
+
+		_, err := r.Importer.StateContext(context.Background(), d, testProviderMeta(t, http.NewServeMux()))
+		assert.ErrorContains(t, err, "invalid")
+	})
+}
+
+// TestResourceGrant_createBeforeDestroy replaces a grant with an identical grant, e.g. with
+// `terraform apply -replace`. Infra returns the existing grant to the new instance, so
+// deleting the old instance must not delete the grant.
+func TestResourceGrant_createBeforeDestroy(t *testing.T) {
+	group := api.Group{ID: uid.New(), Name: "developers"}
+	grant := api.Grant{ID: uid.New(), Group: group.ID, Privilege: "view", Resource: "infra"}
+
+	deleted := false
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/groups/"+group.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, group)
+	})
+	mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		testWriteJSON(t, w, http.StatusOK, api.CreateGrantResponse{Grant: &grant, WasCreated: false})
+	})
+	mux.HandleFunc("/api/grants/"+grant.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = true
+		}
+
+		testWriteJSON(t, w, http.StatusOK, grant)
+	})
+
+	meta := testProviderMeta(t, mux)
+
+	config := map[string]interface{}{
+		"group_id": group.ID.String(),
+		"infra":    []interface{}{map[string]interface{}{"role": "view"}},
+	}
+
+	replacement := schema.TestResourceDataRaw(t, resourceGrant().Schema, config)
+	diags := resourceGrantCreate(context.Background(), replacement, meta)
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+	assert.Equal(t, replacement.Id(), grant.ID.String())
+
+	old := schema.TestResourceDataRaw(t, resourceGrant().Schema, config)
+	old.SetId(grant.ID.String())
+
+	diags = resourceGrantDelete(context.Background(), old, meta)
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Summary, "Grant left in Infra")
+	assert.Equal(t, old.Id(), "")
+	assert.Assert(t, !deleted, "the grant was deleted")
+
+	// the replacement adopted the grant, which is recorded in its state
+	assert.Equal(t, replacement.Get("adopted"), true)
+
+	diags = resourceGrantDelete(context.Background(), replacement, meta)
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Summary, "Grant left in Infra")
+	assert.Assert(t, !deleted, "the grant was deleted")
+}
+
+// TestResourceGrant_adopted deletes a grant which was adopted in an earlier run, e.g. because
+// another infra_grant resource manages the same grant.
+func TestResourceGrant_adopted(t *testing.T) {
+	id := uid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceGrant().Schema, map[string]interface{}{
+		"group_id": uid.New().String(),
+		"infra":    []interface{}{map[string]interface{}{"role": "view"}},
+	})
+	d.SetId(id.String())
+	assert.NilError(t, d.Set("adopted", true))
+
+	diags := resourceGrantDelete(context.Background(), d, testProviderMeta(t, mux))
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Summary, "Grant left in Infra")
+	assert.Equal(t, d.Id(), "")
+}
+
+// TestResourceGrant_updateExpiresIn changes `expires_in`, which updates the grant in place
+// instead of replacing it with an identical grant.
+func TestResourceGrant_updateExpiresIn(t *testing.T) {
+	group := api.Group{ID: uid.New(), Name: "developers"}
+	grant := api.Grant{ID: uid.New(), Group: group.ID, Privilege: "view", Resource: "infra"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/groups/"+group.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, group)
+	})
+	mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		testWriteJSON(t, w, http.StatusOK, api.CreateGrantResponse{Grant: &grant, WasCreated: false})
+	})
+	mux.HandleFunc("/api/grants/"+grant.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodGet)
+		testWriteJSON(t, w, http.StatusOK, grant)
+	})
+
+	meta := testProviderMeta(t, mux)
+	ctx := context.Background()
+
+	r := resourceGrant()
+	state := &terraform.InstanceState{
+		ID: grant.ID.String(),
+		Attributes: map[string]string{
+			"id":                    grant.ID.String(),
+			"group_id":              group.ID.String(),
+			"group_name":            group.Name,
+			"infra.#":               "1",
+			"infra.0.role":          "view",
+			"expires_in":            "1h",
+			"expires_at":            time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			"allow_system_identity": "false",
+			"adopted":               "false",
+			"expired":               "false",
+		},
+	}
+
+	config := map[string]interface{}{
+		"group_id":   group.ID.String(),
+		"expires_in": "24h",
+		"infra":      []interface{}{map[string]interface{}{"role": "view"}},
+	}
+
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+	assert.NilError(t, err)
+	assert.Assert(t, !diff.RequiresNew())
+	assert.Assert(t, diff.Attributes["expires_at"].NewComputed)
+
+	newState, diags := r.Apply(ctx, state, diff, meta)
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+	assert.Equal(t, newState.ID, grant.ID.String())
+	assert.Equal(t, newState.Attributes["adopted"], "false")
+
+	expiresAt, err := time.Parse(time.RFC3339, newState.Attributes["expires_at"])
+	assert.NilError(t, err)
+	assert.Assert(t, time.Until(expiresAt) > 23*time.Hour, expiresAt)
+}
+
+func TestResourceGrant_forceNew(t *testing.T) {
+	groupID := uid.New()
+
+	meta := testProviderMeta(t, http.NewServeMux())
+
+	id := uid.New()
+
+	r := resourceGrant()
+	state := &terraform.InstanceState{
+		ID: id.String(),
+		Attributes: map[string]string{
+			"id":                    id.String(),
+			"group_id":              groupID.String(),
+			"kubernetes.#":          "1",
+			"kubernetes.0.role":     "view",
+			"kubernetes.0.cluster":  "production",
+			"allow_system_identity": "false",
+			"expired":               "false",
+		},
+	}
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expected bool
+	}{
+		"role": {
+			config: map[string]interface{}{
+				"kubernetes": []interface{}{map[string]interface{}{"role": "edit", "cluster": "production"}},
+			},
+		},
+		"cluster": {
+			config: map[string]interface{}{
+				"kubernetes": []interface{}{map[string]interface{}{"role": "view", "cluster": "staging"}},
+			},
+			expected: true,
+		},
+		"namespace": {
+			config: map[string]interface{}{
+				"kubernetes": []interface{}{map[string]interface{}{"role": "view", "cluster": "production", "namespace": "web"}},
+			},
+			expected: true,
+		},
+		"kind": {
+			config: map[string]interface{}{
+				"infra": []interface{}{map[string]interface{}{"role": "view"}},
+			},
+			expected: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.config["group_id"] = groupID.String()
+
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(tc.config), meta)
+			assert.NilError(t, err)
+			assert.Equal(t, diff.RequiresNew(), tc.expected)
+		})
+	}
+}
+
+func TestResourceGrant_expired(t *testing.T) {
+	group := api.Group{ID: uid.New(), Name: "administrators"}
+	grant := api.Grant{ID: uid.New(), Group: group.ID, Privilege: "admin", Resource: "infra"}
+	other := api.Grant{ID: uid.New(), User: uid.New(), Privilege: "admin", Resource: "infra"}
+
+	r := resourceGrant()
+	state := &terraform.InstanceState{
+		ID: grant.ID.String(),
+		Attributes: map[string]string{
+			"id":                    grant.ID.String(),
+			"group_id":              group.ID.String(),
+			"group_name":            group.Name,
+			"infra.#":               "1",
+			"infra.0.role":          "admin",
+			"expires_at":            "2022-10-01T12:00:00Z",
+			"allow_system_identity": "false",
+			"expired":               "false",
+		},
+	}
+
+	config := map[string]interface{}{
+		"group_id":   group.ID.String(),
+		"expires_at": "2022-10-01T12:00:00Z",
+		"infra":      []interface{}{map[string]interface{}{"role": "admin"}},
+	}
+
+	setup := func(t *testing.T, adminGrants []api.Grant) (*providerMeta, *bool) {
+		deleted := false
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/groups/"+group.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+			testWriteJSON(t, w, http.StatusOK, group)
+		})
+		mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+			testWriteJSON(t, w, http.StatusOK, testListResponse(adminGrants...))
+		})
+		mux.HandleFunc("/api/grants/"+grant.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodDelete:
+				deleted = true
+				testWriteJSON(t, w, http.StatusOK, api.EmptyResponse{})
+			case deleted:
+				testWriteJSON(t, w, http.StatusNotFound, api.Error{Code: http.StatusNotFound, Message: "not found"})
+			default:
+				testWriteJSON(t, w, http.StatusOK, grant)
+			}
+		})
+
+		return testProviderMeta(t, mux), &deleted
+	}
+
+	t.Run("removed from Infra and then from the state", func(t *testing.T) {
+		meta, deleted := setup(t, []api.Grant{grant, other})
+		ctx := context.Background()
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+		assert.Equal(t, diff.Attributes["expired"].New, "true")
+
+		newState, diags := r.Apply(ctx, state, diff, meta)
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+		assert.Assert(t, *deleted, "the grant was not deleted")
+		assert.Equal(t, newState.ID, grant.ID.String())
+		assert.Equal(t, newState.Attributes["expired"], "true")
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, newState, meta)
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+		assert.Assert(t, refreshed == nil, "%v", refreshed)
+	})
+
+	t.Run("last Infra admin grant", func(t *testing.T) {
+		meta, deleted := setup(t, []api.Grant{grant})
+
+		_, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+		assert.ErrorIs(t, err, errLastInfraAdminGrant)
+		assert.Assert(t, !*deleted, "the grant was deleted")
+	})
+}
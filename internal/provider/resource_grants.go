@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+func resourceGrants() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a set of Infra grants. This resource can be used to assign every combination of users, groups, and Kubernetes roles in a single resource. Only grants which are added or removed are changed.",
+
+		CreateContext: resourceGrantsCreate,
+		ReadContext:   resourceGrantsRead,
+		UpdateContext: resourceGrantsUpdate,
+		DeleteContext: resourceGrantsDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The grant set's unique identifier.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"user_ids": {
+				Description: "The IDs of the users to assign these grants.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateStringIsID(),
+				},
+				AtLeastOneOf: []string{
+					"user_ids", "group_ids",
+				},
+			},
+			"group_ids": {
+				Description: "The IDs of the groups to assign these grants.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateStringIsID(),
+				},
+				AtLeastOneOf: []string{
+					"user_ids", "group_ids",
+				},
+			},
+			"kubernetes": {
+				Description: "Kubernetes grant configurations. Each user and group is assigned every configuration.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Description: "The name of the Kubernetes ClusterRole to assign. See [Kubernetes Roles](https://infrahq.com/docs/integrations/kubernetes#roles) for a list of valid roles.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"cluster": {
+							Description: "The name of the Kubernetes cluster to assign.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"namespace": {
+							Description: "The namespace of the Kubernetes cluster to assign.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceGrantsCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	grants, err := expandGrants(d.Get("user_ids"), d.Get("group_ids"), d.Get("kubernetes"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing, err := listGrantsForSubjects(ctx, client, grants)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	request := &api.UpdateGrantsRequest{
+		GrantsToAdd: grantsDifference(grants, existing),
+	}
+
+	if len(request.GrantsToAdd) > 0 {
+		if err := updateGrants(ctx, client, request); err != nil {
+			return apiErrorDiagnostics(err, nil)
+		}
+	}
+
+	d.SetId(resource.UniqueId())
+	return resourceGrantsRead(ctx, d, m)
+}
+
+func resourceGrantsRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	grants, err := expandGrants(d.Get("user_ids"), d.Get("group_ids"), d.Get("kubernetes"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing, err := listGrantsForSubjects(ctx, client, grants)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	// a user or group which is missing any of its grants is removed from the state so
+	// the missing grants are added back during the next apply
+	missing := make(map[string]bool)
+	for _, grant := range grantsDifference(grants, existing) {
+		missing[grantSubjectKey(grant)] = true
+	}
+
+	var userIDs, groupIDs []string
+	for _, grant := range grants {
+		if missing[grantSubjectKey(grant)] {
+			continue
+		}
+
+		if grant.User != 0 {
+			userIDs = appendUnique(userIDs, grant.User.String())
+		}
+
+		if grant.Group != 0 {
+			groupIDs = appendUnique(groupIDs, grant.Group.String())
+		}
+	}
+
+	if err := d.Set("user_ids", userIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("group_ids", groupIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	return diags
+}
+
+func resourceGrantsUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	oldUserIDs, newUserIDs := d.GetChange("user_ids")
+	oldGroupIDs, newGroupIDs := d.GetChange("group_ids")
+	oldKubernetes, newKubernetes := d.GetChange("kubernetes")
+
+	oldGrants, err := expandGrants(oldUserIDs, oldGroupIDs, oldKubernetes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	newGrants, err := expandGrants(newUserIDs, newGroupIDs, newKubernetes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing, err := listGrantsForSubjects(ctx, client, append(oldGrants, newGrants...))
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	request := &api.UpdateGrantsRequest{
+		GrantsToAdd:    grantsDifference(newGrants, existing),
+		GrantsToRemove: grantsIntersection(grantsDifference(oldGrants, newGrants), existing),
+	}
+
+	if len(request.GrantsToAdd) > 0 || len(request.GrantsToRemove) > 0 {
+		if err := updateGrants(ctx, client, request); err != nil {
+			return apiErrorDiagnostics(err, nil)
+		}
+	}
+
+	return resourceGrantsRead(ctx, d, m)
+}
+
+func resourceGrantsDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	grants, err := expandGrants(d.Get("user_ids"), d.Get("group_ids"), d.Get("kubernetes"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing, err := listGrantsForSubjects(ctx, client, grants)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	request := &api.UpdateGrantsRequest{
+		GrantsToRemove: grantsIntersection(grants, existing),
+	}
+
+	if len(request.GrantsToRemove) > 0 {
+		if err := updateGrants(ctx, client, request); err != nil {
+			return apiErrorDiagnostics(err, nil)
+		}
+	}
+
+	d.SetId("")
+
+	var diags diag.Diagnostics
+	return diags
+}
+
+// expandGrants returns a grant request for every combination of user or group and
+// Kubernetes configuration.
+func expandGrants(userIDs, groupIDs, kubernetes any) ([]api.GrantRequest, error) {
+	var grants []api.GrantRequest
+
+	for _, item := range kubernetes.(*schema.Set).List() {
+		target := item.(map[string]interface{})
+
+		resource := target["cluster"].(string)
+		if namespace := target["namespace"].(string); namespace != "" {
+			resource = fmt.Sprintf("%s.%s", resource, namespace)
+		}
+
+		for _, s := range userIDs.(*schema.Set).List() {
+			userID, err := uid.Parse([]byte(s.(string)))
+			if err != nil {
+				return nil, err
+			}
+
+			grants = append(grants, api.GrantRequest{User: userID, Resource: resource, Privilege: target["role"].(string)})
+		}
+
+		for _, s := range groupIDs.(*schema.Set).List() {
+			groupID, err := uid.Parse([]byte(s.(string)))
+			if err != nil {
+				return nil, err
+			}
+
+			grants = append(grants, api.GrantRequest{Group: groupID, Resource: resource, Privilege: target["role"].(string)})
+		}
+	}
+
+	return grants, nil
+}
+
+// listGrantsForSubjects lists the existing grants of every user and group in grants.
+func listGrantsForSubjects(ctx context.Context, client *api.Client, grants []api.GrantRequest) ([]api.GrantRequest, error) {
+	seen := make(map[string]bool)
+
+	var existing []api.GrantRequest
+	for _, grant := range grants {
+		if seen[grantSubjectKey(grant)] {
+			continue
+		}
+
+		seen[grantSubjectKey(grant)] = true
+
+		items, err := listAll(func(page int) (*api.ListResponse[api.Grant], error) {
+			return client.ListGrants(ctx, api.ListGrantsRequest{
+				User:  grant.User,
+				Group: grant.Group,
+				PaginationRequest: api.PaginationRequest{
+					Page:  page,
+					Limit: 1000,
+				},
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			existing = append(existing, api.GrantRequest{User: item.User, Group: item.Group, Resource: item.Resource, Privilege: item.Privilege})
+		}
+	}
+
+	return existing, nil
+}
+
+func grantSubjectKey(grant api.GrantRequest) string {
+	return fmt.Sprintf("%s/%s", grant.User, grant.Group)
+}
+
+func grantKey(grant api.GrantRequest) string {
+	return fmt.Sprintf("%s/%s/%s", grantSubjectKey(grant), grant.Resource, grant.Privilege)
+}
+
+// grantsDifference returns the grants in a which are not in b.
+func grantsDifference(a, b []api.GrantRequest) []api.GrantRequest {
+	keys := make(map[string]bool, len(b))
+	for _, grant := range b {
+		keys[grantKey(grant)] = true
+	}
+
+	var result []api.GrantRequest
+	for _, grant := range a {
+		if !keys[grantKey(grant)] {
+			result = append(result, grant)
+		}
+	}
+
+	return result
+}
+
+// grantsIntersection returns the grants in a which are also in b.
+func grantsIntersection(a, b []api.GrantRequest) []api.GrantRequest {
+	return grantsDifference(a, grantsDifference(a, b))
+}
+
+func appendUnique(items []string, s string) []string {
+	if containsString(items, s) {
+		return items
+	}
+
+	return append(items, s)
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
+)
+
+func TestAccResourceGrants(t *testing.T) {
+	email1 := randomEmail()
+	email2 := randomEmail()
+	name := randomName()
+
+	cluster := randomName("cluster")
+	namespace := randomName("ns")
+
+	resourceName := "infra_grants.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrants(email1, email2, name, cluster, namespace, `[infra_user.test1.id]`, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "user_ids.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group_ids.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "kubernetes.#", "2"),
+				),
+			},
+			{
+				Config: testAccResourceGrants(email1, email2, name, cluster, namespace, `[infra_user.test1.id, infra_user.test2.id]`, `[infra_group.test.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "user_ids.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "group_ids.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "kubernetes.#", "2"),
+				),
+			},
+			{
+				Config: testAccResourceGrants(email1, email2, name, cluster, namespace, `[]`, `[infra_group.test.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "user_ids.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "group_ids.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceGrants(email1, email2, name, cluster, namespace, userIDs, groupIDs string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "test1" {
+	name = "%[1]s"
+}
+
+resource "infra_user" "test2" {
+	name = "%[2]s"
+}
+
+resource "infra_group" "test" {
+	name = "%[3]s"
+}
+
+resource "infra_grants" "test" {
+	user_ids = %[6]s
+	group_ids = %[7]s
+
+	kubernetes {
+		cluster = "%[4]s"
+		role = "view"
+	}
+
+	kubernetes {
+		cluster = "%[4]s"
+		namespace = "%[5]s"
+		role = "edit"
+	}
+}`, email1, email2, name, cluster, namespace, userIDs, groupIDs)
+}
+
+func TestExpandGrants(t *testing.T) {
+	users := schema.NewSet(schema.HashString, []interface{}{"2", "3"})
+	groups := schema.NewSet(schema.HashString, []interface{}{"4"})
+	kubernetes := schema.NewSet(schema.HashResource(resourceGrants().Schema["kubernetes"].Elem.(*schema.Resource)), []interface{}{
+		map[string]interface{}{"cluster": "cluster", "namespace": "", "role": "view"},
+		map[string]interface{}{"cluster": "cluster", "namespace": "default", "role": "edit"},
+	})
+
+	grants, err := expandGrants(users, groups, kubernetes)
+	assert.NilError(t, err)
+
+	keys := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		keys = append(keys, grantKey(grant))
+	}
+
+	assert.Equal(t, len(keys), 6)
+	for _, expected := range []string{
+		"2//cluster/view", "3//cluster/view", "/4/cluster/view",
+		"2//cluster.default/edit", "3//cluster.default/edit", "/4/cluster.default/edit",
+	} {
+		assert.Assert(t, containsString(keys, expected), expected)
+	}
+}
+
+func TestGrantsDifference(t *testing.T) {
+	a := []api.GrantRequest{
+		{User: 1, Resource: "cluster", Privilege: "view"},
+		{User: 1, Resource: "cluster", Privilege: "edit"},
+		{Group: 1, Resource: "cluster", Privilege: "view"},
+	}
+
+	b := []api.GrantRequest{
+		{User: 1, Resource: "cluster", Privilege: "view"},
+		{User: 2, Resource: "cluster", Privilege: "view"},
+	}
+
+	assert.DeepEqual(t, grantsDifference(a, b), []api.GrantRequest{
+		{User: 1, Resource: "cluster", Privilege: "edit"},
+		{Group: 1, Resource: "cluster", Privilege: "view"},
+	})
+
+	assert.DeepEqual(t, grantsIntersection(a, b), []api.GrantRequest{
+		{User: 1, Resource: "cluster", Privilege: "view"},
+	})
+}
+
+func TestListGrantsForSubjects(t *testing.T) {
+	pages := [][]api.Grant{
+		{{User: 1, Resource: "cluster", Privilege: "view"}},
+		{{User: 1, Resource: "cluster", Privilege: "edit"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		assert.NilError(t, err)
+
+		response := testListResponse(pages[page-1]...)
+		response.Page = page
+		response.TotalPages = len(pages)
+
+		testWriteJSON(t, w, http.StatusOK, response)
+	})
+
+	client := testProviderMeta(t, mux).client
+
+	grants, err := listGrantsForSubjects(context.Background(), client, []api.GrantRequest{{User: 1}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, grants, []api.GrantRequest{
+		{User: 1, Resource: "cluster", Privilege: "view"},
+		{User: 1, Resource: "cluster", Privilege: "edit"},
+	})
+}
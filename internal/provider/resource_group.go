@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -12,14 +14,26 @@ import (
 	"github.com/infrahq/infra/uid"
 )
 
+var groupAPIFields = apiFieldPaths{
+	"name": cty.GetAttrPath("name"),
+}
+
 func resourceGroup() *schema.Resource {
 	return &schema.Resource{
 		Description: "Groups are used in Infra to manage a collection of users. A group can then be associated with a role and cluster via a grant and all users with the group will gain that role and and corresponding access to the cluster.",
 
 		CreateContext: resourceGroupCreate,
 		ReadContext:   resourceGroupRead,
+		UpdateContext: resourceGroupUpdate,
 		DeleteContext: resourceGroupDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -36,17 +50,29 @@ func resourceGroup() *schema.Resource {
 				Required:    true,
 				ForceNew:    true,
 			},
+			"adopt_existing": {
+				Description: "Manage an existing group with the same name instead of failing when the group already exists. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
 		},
 	}
 }
 
 func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	name := strings.TrimSpace(d.Get("name").(string))
 	group, err := client.CreateGroup(ctx, &api.CreateGroupRequest{Name: name})
 	if err != nil {
-		return diag.FromErr(err)
+		if api.ErrorStatusCode(err) != http.StatusConflict || !d.Get("adopt_existing").(bool) {
+			return apiErrorDiagnostics(err, groupAPIFields)
+		}
+
+		group, err = groupFromName(ctx, client, name)
+		if err != nil {
+			return apiErrorDiagnostics(err, groupAPIFields)
+		}
 	}
 
 	d.SetId(group.ID.String())
@@ -54,7 +80,7 @@ func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, m any) dia
 }
 
 func resourceGroupRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -63,7 +89,7 @@ func resourceGroupRead(ctx context.Context, d *schema.ResourceData, m any) diag.
 
 	group, err := client.GetGroup(ctx, id)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, groupAPIFields)
 	}
 
 	if err := d.Set("name", group.Name); err != nil {
@@ -74,8 +100,14 @@ func resourceGroupRead(ctx context.Context, d *schema.ResourceData, m any) diag.
 	return diags
 }
 
+// resourceGroupUpdate only stores changes to `adopt_existing` since all other attributes
+// force a new group.
+func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	return resourceGroupRead(ctx, d, m)
+}
+
 func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -83,7 +115,7 @@ func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, m any) dia
 	}
 
 	if err := client.DeleteGroup(ctx, id); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, groupAPIFields)
 	}
 
 	d.SetId("")
@@ -94,12 +126,7 @@ func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, m any) dia
 
 func groupFromIDOrName(ctx context.Context, client *api.Client, d *schema.ResourceData, id, name string) (*api.Group, error) {
 	if s := d.Get(id).(string); s != "" {
-		groupID, err := uid.Parse([]byte(s))
-		if err != nil {
-			return nil, err
-		}
-
-		return client.GetGroup(ctx, groupID)
+		return groupFromID(ctx, client, s)
 	}
 
 	if s := d.Get(name).(string); s != "" {
@@ -109,6 +136,15 @@ func groupFromIDOrName(ctx context.Context, client *api.Client, d *schema.Resour
 	return nil, fmt.Errorf("one of `%s,%s` must be specified", id, name)
 }
 
+func groupFromID(ctx context.Context, client *api.Client, id string) (*api.Group, error) {
+	groupID, err := uid.Parse([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetGroup(ctx, groupID)
+}
+
 func groupFromName(ctx context.Context, client *api.Client, name string) (*api.Group, error) {
 	request := api.ListGroupsRequest{
 		Name: name,
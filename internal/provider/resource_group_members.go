@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+func resourceGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides the complete list of members of an Infra group. Users added to the group outside of Terraform will be removed.
+
+~> This resource cannot be used with ` + "`infra_group_membership`" + ` for the same group.`,
+
+		CreateContext: resourceGroupMembersCreate,
+		ReadContext:   resourceGroupMembersRead,
+		UpdateContext: resourceGroupMembersUpdate,
+		DeleteContext: resourceGroupMembersDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The group's unique identifier.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"group_id": {
+				Description:      "The ID of the group.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateStringIsID(),
+				ExactlyOneOf: []string{
+					"group_id", "group_name",
+				},
+			},
+			"group_name": {
+				Description: "The name of the group.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				ExactlyOneOf: []string{
+					"group_id", "group_name",
+				},
+			},
+			"user_ids": {
+				Description: "The IDs of the users who are members of the group.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateStringIsID(),
+				},
+			},
+		},
+	}
+}
+
+func resourceGroupMembersCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	group, err := groupFromIDOrName(ctx, client, d, "group_id", "group_name")
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	if err := setGroupMembers(ctx, client, group.ID, d.Get("user_ids").(*schema.Set)); err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	d.SetId(group.ID.String())
+	return resourceGroupMembersRead(ctx, d, m)
+}
+
+func resourceGroupMembersRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	id, err := ParseID(d, "id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	group, err := client.GetGroup(ctx, id)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	members, err := groupMembers(ctx, client, group.ID)
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.String())
+	}
+
+	if err := d.Set("group_id", group.ID.String()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("group_name", group.Name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("user_ids", userIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	return diags
+}
+
+func resourceGroupMembersUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	id, err := ParseID(d, "id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("user_ids") {
+		if err := setGroupMembers(ctx, client, id, d.Get("user_ids").(*schema.Set)); err != nil {
+			return apiErrorDiagnostics(err, nil)
+		}
+	}
+
+	return resourceGroupMembersRead(ctx, d, m)
+}
+
+func resourceGroupMembersDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	id, err := ParseID(d, "id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setGroupMembers(ctx, client, id, schema.NewSet(schema.HashString, nil)); err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	d.SetId("")
+
+	var diags diag.Diagnostics
+	return diags
+}
+
+// setGroupMembers adds and removes users from the group so the group's members are
+// exactly userIDs.
+func setGroupMembers(ctx context.Context, client *api.Client, groupID uid.ID, userIDs *schema.Set) error {
+	members, err := groupMembers(ctx, client, groupID)
+	if err != nil {
+		return err
+	}
+
+	request := &api.UpdateUsersInGroupRequest{
+		GroupID: groupID,
+	}
+
+	current := make(map[uid.ID]bool, len(members))
+	for _, member := range members {
+		current[member] = true
+
+		if !userIDs.Contains(member.String()) {
+			request.UserIDsToRemove = append(request.UserIDsToRemove, member)
+		}
+	}
+
+	for _, s := range userIDs.List() {
+		userID, err := uid.Parse([]byte(s.(string)))
+		if err != nil {
+			return err
+		}
+
+		if !current[userID] {
+			request.UserIDsToAdd = append(request.UserIDsToAdd, userID)
+		}
+	}
+
+	if len(request.UserIDsToAdd) == 0 && len(request.UserIDsToRemove) == 0 {
+		return nil
+	}
+
+	return client.UpdateUsersInGroup(ctx, request)
+}
+
+func groupMembers(ctx context.Context, client *api.Client, groupID uid.ID) ([]uid.ID, error) {
+	users, err := listAll(func(page int) (*api.ListResponse[api.User], error) {
+		return client.ListUsers(ctx, api.ListUsersRequest{
+			Group: groupID,
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]uid.ID, 0, len(users))
+	for _, user := range users {
+		members = append(members, user.ID)
+	}
+
+	return members, nil
+}
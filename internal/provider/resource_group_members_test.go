@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceGroupMembers(t *testing.T) {
+	email1 := randomEmail()
+	email2 := randomEmail()
+	name := randomName()
+
+	resourceName := fmt.Sprintf("infra_group_members.%s", t.Name())
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGroupMembers(t, email1, email2, name, fmt.Sprintf("[infra_user.%s_1.id]", t.Name())),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "group_name", name),
+					resource.TestCheckResourceAttr(resourceName, "user_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "user_ids.*", fmt.Sprintf("infra_user.%s_1", t.Name()), "id"),
+				),
+			},
+			{
+				Config: testAccResourceGroupMembers(t, email1, email2, name, fmt.Sprintf("[infra_user.%[1]s_1.id, infra_user.%[1]s_2.id]", t.Name())),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "user_ids.#", "2"),
+				),
+			},
+			{
+				Config: testAccResourceGroupMembers(t, email1, email2, name, fmt.Sprintf("[infra_user.%s_2.id]", t.Name())),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "user_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "user_ids.*", fmt.Sprintf("infra_user.%s_2", t.Name()), "id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceGroupMembers(t *testing.T, email1, email2, name, userIDs string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "%[1]s_1" {
+	name = "%[2]s"
+}
+
+resource "infra_user" "%[1]s_2" {
+	name = "%[3]s"
+}
+
+resource "infra_group" "%[1]s" {
+	name = "%[4]s"
+}
+
+resource "infra_group_members" "%[1]s" {
+	group_id = infra_group.%[1]s.id
+	user_ids = %[5]s
+}`, t.Name(), email1, email2, name, userIDs)
+}
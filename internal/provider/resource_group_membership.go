@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,6 +20,21 @@ func resourceGroupMembership() *schema.Resource {
 		ReadContext:   resourceGroupMembershipRead,
 		DeleteContext: resourceGroupMembershipDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceGroupMembershipV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeGroupMembershipID,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"user_id": {
 				Description:      "The ID of the user to assign to the group.",
@@ -68,18 +84,18 @@ func resourceGroupMembership() *schema.Resource {
 }
 
 func resourceGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	var diags diag.Diagnostics
 
 	user, err := userFromIDOrEmail(ctx, client, d, "user_id", "user_name")
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{Summary: err.Error()})
+		diags = append(diags, apiErrorDiagnostics(err, nil)...)
 	}
 
 	group, err := groupFromIDOrName(ctx, client, d, "group_id", "group_name")
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{Summary: err.Error()})
+		diags = append(diags, apiErrorDiagnostics(err, nil)...)
 	}
 
 	if diags.HasError() {
@@ -92,7 +108,7 @@ func resourceGroupMembershipCreate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if err := client.UpdateUsersInGroup(ctx, request); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, nil)
 	}
 
 	if err := d.Set("user_id", user.ID.String()); err != nil {
@@ -103,12 +119,12 @@ func resourceGroupMembershipCreate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	d.SetId(fmt.Sprintf("%s/%s", user.Name, group.Name))
+	d.SetId(groupMembershipID(user.ID, group.ID))
 	return resourceGroupMembershipRead(ctx, d, m)
 }
 
 func resourceGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	userID, err := ParseID(d, "user_id")
 	if err != nil {
@@ -117,6 +133,14 @@ func resourceGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m
 
 	user, err := client.GetUser(ctx, userID)
 	if err != nil {
+		// the user was deleted outside of terraform
+		if api.ErrorStatusCode(err) == http.StatusNotFound {
+			d.SetId("")
+
+			var diags diag.Diagnostics
+			return diags
+		}
+
 		return diag.FromErr(err)
 	}
 
@@ -125,9 +149,17 @@ func resourceGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	group, err := client.GetGroup(ctx, groupID)
+	group, err := userGroup(ctx, client, user.ID, groupID)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	// the user was removed from the group, or the group was deleted, outside of terraform
+	if group == nil {
+		d.SetId("")
+
+		var diags diag.Diagnostics
+		return diags
 	}
 
 	if err := d.Set("user_name", user.Name); err != nil {
@@ -143,7 +175,7 @@ func resourceGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	userID, err := ParseID(d, "user_id")
 	if err != nil {
@@ -161,7 +193,7 @@ func resourceGroupMembershipDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if err := client.UpdateUsersInGroup(ctx, request); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, nil)
 	}
 
 	d.SetId("")
@@ -169,3 +201,48 @@ func resourceGroupMembershipDelete(ctx context.Context, d *schema.ResourceData,
 	var diags diag.Diagnostics
 	return diags
 }
+
+func groupMembershipID(userID, groupID uid.ID) string {
+	return fmt.Sprintf("%s/%s", userID, groupID)
+}
+
+// userGroup returns the group with groupID if the user is a member, or nil if not.
+func userGroup(ctx context.Context, client *api.Client, userID, groupID uid.ID) (*api.Group, error) {
+	groups, err := listUserGroups(ctx, client, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range groups {
+		if groups[i].ID == groupID {
+			return &groups[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// upgradeGroupMembershipID replaces the `<user_name>/<group_name>` ID used before
+// version 1 with `<user_id>/<group_id>`, which does not change when a user or group
+// is renamed or recreated.
+func upgradeGroupMembershipID(ctx context.Context, rawState map[string]interface{}, meta any) (map[string]interface{}, error) {
+	userID, _ := rawState["user_id"].(string)
+	groupID, _ := rawState["group_id"].(string)
+
+	if userID == "" || groupID == "" {
+		return nil, fmt.Errorf("unexpected state for group membership %v: user_id and group_id are required", rawState["id"])
+	}
+
+	user, err := uid.Parse([]byte(userID))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected state for group membership %v: %w", rawState["id"], err)
+	}
+
+	group, err := uid.Parse([]byte(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected state for group membership %v: %w", rawState["id"], err)
+	}
+
+	rawState["id"] = groupMembershipID(user, group)
+	return rawState, nil
+}
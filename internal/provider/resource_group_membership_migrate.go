@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceGroupMembershipV0 is the schema of infra_group_membership when its ID was
+// `<user_name>/<group_name>`. Only the types are needed to decode existing state.
+func resourceGroupMembershipV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id":         {Type: schema.TypeString, Computed: true},
+			"user_id":    {Type: schema.TypeString, Optional: true, Computed: true},
+			"user_name":  {Type: schema.TypeString, Optional: true, Computed: true},
+			"group_id":   {Type: schema.TypeString, Optional: true, Computed: true},
+			"group_name": {Type: schema.TypeString, Optional: true, Computed: true},
+		},
+	}
+}
@@ -1,10 +1,18 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
 )
 
 func TestAccResourceGroupMembership(t *testing.T) {
@@ -14,8 +22,8 @@ func TestAccResourceGroupMembership(t *testing.T) {
 	resourceName := fmt.Sprintf("infra_group_membership.%s", t.Name())
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: composeTestConfigFunc(
@@ -26,6 +34,7 @@ func TestAccResourceGroupMembership(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(resourceName, "user_name", email),
 					resource.TestCheckResourceAttr(resourceName, "group_name", name),
+					testCheckGroupMembershipID(resourceName),
 				),
 			},
 			{
@@ -54,6 +63,22 @@ func TestAccResourceGroupMembership(t *testing.T) {
 	})
 }
 
+func testCheckGroupMembershipID(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		expected := fmt.Sprintf("%s/%s", rs.Primary.Attributes["user_id"], rs.Primary.Attributes["group_id"])
+		if rs.Primary.ID != expected {
+			return fmt.Errorf("expected ID %s, got %s", expected, rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
 func testAccResourceGroupMembership(t *testing.T) string {
 	return fmt.Sprintf(`
 resource "infra_group_membership" "%[1]s" {
@@ -85,3 +110,70 @@ resource "infra_group_membership" "%[1]s" {
 	]
 }`, t.Name(), name)
 }
+
+func TestResourceGroupMembershipRead(t *testing.T) {
+	userID := uid.New()
+	groupID := uid.New()
+
+	type testCase struct {
+		user       *api.User
+		groups     []api.Group
+		expectedID string
+	}
+
+	run := func(t *testing.T, tc testCase) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/users/"+userID.String(), func(w http.ResponseWriter, r *http.Request) {
+			if tc.user == nil {
+				testWriteJSON(t, w, http.StatusNotFound, api.Error{Code: http.StatusNotFound, Message: "not found"})
+				return
+			}
+
+			testWriteJSON(t, w, http.StatusOK, tc.user)
+		})
+		mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.URL.Query().Get("userID"), userID.String())
+			testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Group]{Count: len(tc.groups), Items: tc.groups})
+		})
+
+		d := schema.TestResourceDataRaw(t, resourceGroupMembership().Schema, map[string]interface{}{
+			"user_id":  userID.String(),
+			"group_id": groupID.String(),
+		})
+		d.SetId(groupMembershipID(userID, groupID))
+
+		diags := resourceGroupMembershipRead(context.Background(), d, testProviderMeta(t, mux))
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+		assert.Equal(t, d.Id(), tc.expectedID)
+
+		if tc.expectedID != "" {
+			assert.Equal(t, d.Get("user_name"), tc.user.Name)
+			assert.Equal(t, d.Get("group_name"), "developers")
+		}
+	}
+
+	testCases := map[string]testCase{
+		"member": {
+			user: &api.User{ID: userID, Name: "alice@example.com"},
+			groups: []api.Group{
+				{ID: uid.New(), Name: "everyone"},
+				{ID: groupID, Name: "developers"},
+			},
+			expectedID: groupMembershipID(userID, groupID),
+		},
+		"removed from group": {
+			user:   &api.User{ID: userID, Name: "alice@example.com"},
+			groups: []api.Group{{ID: uid.New(), Name: "everyone"}},
+		},
+		"group deleted": {
+			user: &api.User{ID: userID, Name: "alice@example.com"},
+		},
+		"user deleted": {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gotest.tools/v3/assert"
 
+	"github.com/infrahq/infra/api"
 	"github.com/infrahq/infra/uid"
 )
 
@@ -21,8 +26,8 @@ func TestAccResourceGroup(t *testing.T) {
 	resourceName := fmt.Sprintf("infra_group.%s", t.Name())
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceGroup(t, name1),
@@ -62,3 +67,45 @@ resource "infra_group" "%[1]s" {
 	name = "%[2]s"
 }`, t.Name(), name)
 }
+
+func TestResourceGroupCreate_adoptExisting(t *testing.T) {
+	group := api.Group{ID: uid.New(), Name: "developers"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			testWriteJSON(t, w, http.StatusConflict, api.Error{Code: http.StatusConflict, Message: "a group with that name already exists"})
+		case http.MethodGet:
+			assert.Equal(t, r.URL.Query().Get("name"), group.Name)
+			testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Group]{Count: 1, Items: []api.Group{group}})
+		}
+	})
+	mux.HandleFunc("/api/groups/"+group.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, group)
+	})
+
+	meta := testProviderMeta(t, mux)
+
+	t.Run("adopt", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceGroup().Schema, map[string]interface{}{
+			"name":           group.Name,
+			"adopt_existing": true,
+		})
+
+		diags := resourceGroupCreate(context.Background(), d, meta)
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+		assert.Equal(t, d.Id(), group.ID.String())
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceGroup().Schema, map[string]interface{}{
+			"name": group.Name,
+		})
+
+		diags := resourceGroupCreate(context.Background(), d, meta)
+		assert.Assert(t, diags.HasError())
+		assert.Equal(t, diags[0].Summary, "a group with that name already exists")
+		assert.Equal(t, d.Id(), "")
+	})
+}
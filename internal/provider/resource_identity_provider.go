@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
@@ -13,6 +14,15 @@ import (
 	"github.com/infrahq/infra/api"
 )
 
+var identityProviderAPIFields = apiFieldPaths{
+	"name":             cty.GetAttrPath("name"),
+	"url":              cty.GetAttrPath("issuer"),
+	"clientID":         cty.GetAttrPath("client_id"),
+	"clientSecret":     cty.GetAttrPath("client_secret"),
+	"clientEmail":      cty.GetAttrPath("google").IndexInt(0).GetAttr("service_account_key"),
+	"domainAdminEmail": cty.GetAttrPath("google").IndexInt(0).GetAttr("admin_email"),
+}
+
 func resourceIdentityProvider() *schema.Resource {
 	return &schema.Resource{
 		Description: "The infra identity provider resource can be used to create and manage identity providers.",
@@ -22,6 +32,13 @@ func resourceIdentityProvider() *schema.Resource {
 		UpdateContext: resourceIdentityProviderUpdate,
 		DeleteContext: resourceIdentityProviderDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -40,7 +57,7 @@ func resourceIdentityProvider() *schema.Resource {
 				ValidateDiagFunc: validateStringIsName(),
 			},
 			"issuer": {
-				Description:      "The identity provider's full authorization server URL. Must start with `https://`.",
+				Description:      "The identity provider's full authorization server URL. Must start with `https://`. Use this for generic OIDC identity providers, such as Keycloak or Auth0.",
 				Type:             schema.TypeString,
 				Optional:         true,
 				Computed:         true,
@@ -60,6 +77,19 @@ func resourceIdentityProvider() *schema.Resource {
 				Required:    true,
 				Sensitive:   true,
 			},
+			"scopes": {
+				Description: "The OIDC scopes Infra requests from the identity provider. Infra sets the scopes for each kind of identity provider; they cannot be configured.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"auth_url": {
+				Description: "The identity provider's authorization endpoint, discovered from `issuer`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 			"azure": {
 				Description: "Azure AD identity provider configurations.",
 				Type:        schema.TypeList,
@@ -134,7 +164,7 @@ func resourceIdentityProvider() *schema.Resource {
 }
 
 func resourceIdentityProviderCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	request := &api.CreateProviderRequest{
 		Name:         d.Get("name").(string),
@@ -143,45 +173,18 @@ func resourceIdentityProviderCreate(ctx context.Context, d *schema.ResourceData,
 		ClientSecret: d.Get("client_secret").(string),
 	}
 
-	for i := range d.Get("azure").([]interface{}) {
-		request.Kind = "azure"
-		request.URL = fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0", d.Get(fmt.Sprintf("azure.%d.tenant_id", i)).(string))
-		break
-	}
-
-	for i := range d.Get("google").([]interface{}) {
-		request.API = &api.ProviderAPICredentials{
-			DomainAdminEmail: d.Get(fmt.Sprintf("google.%d.admin_email", i)).(string),
-		}
-
-		if serviceAccountKey := d.Get(fmt.Sprintf("google.%d.service_account_key", i)).(string); serviceAccountKey != "" {
-			serviceAccountKeyJson, err := structure.ExpandJsonFromString(serviceAccountKey)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			if serviceAccountKey, ok := serviceAccountKeyJson["service_account_key"]; ok {
-				request.API.PrivateKey = api.PEM(serviceAccountKey.(string))
-			}
-
-			if clientEmail, ok := serviceAccountKeyJson["client_email"]; ok {
-				request.API.ClientEmail = clientEmail.(string)
-			}
-		}
-
-		request.Kind = "google"
-		request.URL = "https://accounts.google.com"
-		break
+	kind, url, credentials, err := identityProviderKind(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	for i := range d.Get("okta").([]interface{}) {
-		request.Kind = "okta"
-		request.URL = d.Get(fmt.Sprintf("okta.%d.issuer", i)).(string)
+	if kind != "" {
+		request.Kind, request.URL, request.API = kind, url, credentials
 	}
 
 	provider, err := client.CreateProvider(ctx, request)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, identityProviderAPIFields)
 	}
 
 	d.SetId(provider.ID.String())
@@ -189,7 +192,7 @@ func resourceIdentityProviderCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceIdentityProviderRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -198,7 +201,7 @@ func resourceIdentityProviderRead(ctx context.Context, d *schema.ResourceData, m
 
 	provider, err := client.GetProvider(ctx, id)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, identityProviderAPIFields)
 	}
 
 	if err := d.Set("name", provider.Name); err != nil {
@@ -214,12 +217,20 @@ func resourceIdentityProviderRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
+	if err := d.Set("scopes", provider.Scopes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("auth_url", provider.AuthURL); err != nil {
+		return diag.FromErr(err)
+	}
+
 	var diags diag.Diagnostics
 	return diags
 }
 
 func resourceIdentityProviderUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -234,52 +245,25 @@ func resourceIdentityProviderUpdate(ctx context.Context, d *schema.ResourceData,
 		ClientSecret: d.Get("client_secret").(string),
 	}
 
-	for i := range d.Get("azure").([]interface{}) {
-		request.Kind = "azure"
-		request.URL = fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0", d.Get(fmt.Sprintf("azure.%d.tenant_id", i)).(string))
-		break
-	}
-
-	for i := range d.Get("google").([]interface{}) {
-		request.API = &api.ProviderAPICredentials{
-			DomainAdminEmail: d.Get(fmt.Sprintf("google.%d.admin_email", i)).(string),
-		}
-
-		if serviceAccountKey := d.Get(fmt.Sprintf("google.%d.service_account_key", i)).(string); serviceAccountKey != "" {
-			serviceAccountKeyJson, err := structure.ExpandJsonFromString(serviceAccountKey)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			if serviceAccountKey, ok := serviceAccountKeyJson["service_account_key"]; ok {
-				request.API.PrivateKey = api.PEM(serviceAccountKey.(string))
-			}
-
-			if clientEmail, ok := serviceAccountKeyJson["client_email"]; ok {
-				request.API.ClientEmail = clientEmail.(string)
-			}
-		}
-
-		request.Kind = "google"
-		request.URL = "https://accounts.google.com"
-		break
+	kind, url, credentials, err := identityProviderKind(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	for i := range d.Get("okta").([]interface{}) {
-		request.Kind = "okta"
-		request.URL = d.Get(fmt.Sprintf("okta.%d.issuer", i)).(string)
+	if kind != "" {
+		request.Kind, request.URL, request.API = kind, url, credentials
 	}
 
 	_, err = client.UpdateProvider(ctx, request)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, identityProviderAPIFields)
 	}
 
 	return resourceIdentityProviderRead(ctx, d, m)
 }
 
 func resourceIdentityProviderDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -287,10 +271,50 @@ func resourceIdentityProviderDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if err := client.DeleteProvider(ctx, id); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, identityProviderAPIFields)
 	}
 
 	d.SetId("")
 	var diags diag.Diagnostics
 	return diags
 }
+
+// identityProviderKind returns the kind, URL and API credentials of an identity provider
+// from the azure, google or okta configuration. The kind is empty if none are set.
+func identityProviderKind(d *schema.ResourceData) (kind, url string, credentials *api.ProviderAPICredentials, err error) {
+	if len(d.Get("azure").([]interface{})) > 0 {
+		azure := blockValues(d.Get("azure"))
+		return "azure", fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0", azure["tenant_id"]), nil, nil
+	}
+
+	if len(d.Get("google").([]interface{})) > 0 {
+		google := blockValues(d.Get("google"))
+		credentials = &api.ProviderAPICredentials{
+			DomainAdminEmail: google["admin_email"],
+		}
+
+		if serviceAccountKey := google["service_account_key"]; serviceAccountKey != "" {
+			serviceAccountKeyJson, err := structure.ExpandJsonFromString(serviceAccountKey)
+			if err != nil {
+				return "", "", nil, err
+			}
+
+			if serviceAccountKey, ok := serviceAccountKeyJson["service_account_key"]; ok {
+				credentials.PrivateKey = api.PEM(serviceAccountKey.(string))
+			}
+
+			if clientEmail, ok := serviceAccountKeyJson["client_email"]; ok {
+				credentials.ClientEmail = clientEmail.(string)
+			}
+		}
+
+		return "google", "https://accounts.google.com", credentials, nil
+	}
+
+	if len(d.Get("okta").([]interface{})) > 0 {
+		okta := blockValues(d.Get("okta"))
+		return "okta", okta["issuer"], nil, nil
+	}
+
+	return "", "", nil, nil
+}
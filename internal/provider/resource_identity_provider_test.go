@@ -1,10 +1,16 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
 	"github.com/infrahq/infra/uid"
 )
 
@@ -38,8 +44,8 @@ func TestAccResourceIdentityProvider(t *testing.T) {
 			name2 := randomName("updated")
 
 			resource.UnitTest(t, resource.TestCase{
-				PreCheck:          testAccPreCheck(t),
-				ProviderFactories: testAccProviders(t),
+				PreCheck:                 testAccPreCheck(t),
+				ProtoV5ProviderFactories: testAccProviders(t),
 				Steps: []resource.TestStep{
 					{
 						Config: testCase.ConfigFunc(name1, "client_id", "client_secret"),
@@ -137,3 +143,31 @@ resource "infra_identity_provider" "test" {
 	}
 }`, name, clientID, clientSecret)
 }
+
+func TestResourceIdentityProviderRead(t *testing.T) {
+	provider := api.Provider{
+		ID:       uid.New(),
+		Name:     "keycloak",
+		Kind:     "oidc",
+		URL:      "keycloak.example.com/realms/infra",
+		ClientID: "infra",
+		AuthURL:  "https://keycloak.example.com/realms/infra/protocol/openid-connect/auth",
+		Scopes:   []string{"openid", "email", "groups"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/providers/"+provider.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, provider)
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceIdentityProvider().Schema, map[string]interface{}{})
+	d.SetId(provider.ID.String())
+
+	diags := resourceIdentityProviderRead(context.Background(), d, testProviderMeta(t, mux))
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+
+	assert.Equal(t, d.Get("name"), "keycloak")
+	assert.Equal(t, d.Get("issuer"), "https://keycloak.example.com/realms/infra")
+	assert.Equal(t, d.Get("auth_url"), provider.AuthURL)
+	assert.DeepEqual(t, d.Get("scopes"), []interface{}{"openid", "email", "groups"})
+}
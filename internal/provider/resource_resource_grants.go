@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+func resourceResourceGrants() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides the complete set of Infra grants for a Kubernetes cluster or namespace. Grants added to the cluster or namespace outside of Terraform will be removed. Destroying this resource removes only the grants in the Terraform state.
+
+~> This resource cannot be used with ` + "`infra_grant`" + ` or ` + "`infra_grants`" + ` for the same cluster or namespace.`,
+
+		CreateContext: resourceResourceGrantsCreate,
+		ReadContext:   resourceResourceGrantsRead,
+		UpdateContext: resourceResourceGrantsUpdate,
+		DeleteContext: resourceResourceGrantsDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceResourceGrantsImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The grant set's unique identifier. Format is `<cluster>` or `<cluster>.<namespace>`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"cluster": {
+				Description: "The name of the Kubernetes cluster.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"namespace": {
+				Description: "The namespace of the Kubernetes cluster. If omitted, the grants apply to the entire cluster.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"grant": {
+				Description: "Grant configurations.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {
+							Description:      "The ID of the user to assign this grant.",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validateStringIsID(),
+						},
+						"group_id": {
+							Description:      "The ID of the group to assign this grant.",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validateStringIsID(),
+						},
+						"role": {
+							Description: "The name of the Kubernetes ClusterRole to assign. See [Kubernetes Roles](https://infrahq.com/docs/integrations/kubernetes#roles) for a list of valid roles.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceResourceGrantsCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	resource := GrantResource(d)
+
+	grants, err := expandResourceGrants(resource, d.Get("grant"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setResourceGrants(ctx, client, resource, grants); err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	d.SetId(resource)
+	return resourceResourceGrantsRead(ctx, d, m)
+}
+
+func resourceResourceGrantsRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	existing, err := listGrantsForResource(ctx, client, d.Id())
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	grants := make([]map[string]interface{}, 0, len(existing))
+	for _, item := range existing {
+		grant := make(map[string]interface{})
+		grant["user_id"] = item.User.String()
+		grant["group_id"] = item.Group.String()
+		grant["role"] = item.Privilege
+
+		grants = append(grants, grant)
+	}
+
+	if err := d.Set("grant", grants); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	return diags
+}
+
+func resourceResourceGrantsUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	grants, err := expandResourceGrants(d.Id(), d.Get("grant"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setResourceGrants(ctx, client, d.Id(), grants); err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	return resourceResourceGrantsRead(ctx, d, m)
+}
+
+// resourceResourceGrantsDelete removes the grants in the state. Grants which were added
+// since the last refresh are not removed since Terraform has not seen them.
+func resourceResourceGrantsDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	grants, err := expandResourceGrants(d.Id(), d.Get("grant"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing, err := listGrantsForResource(ctx, client, d.Id())
+	if err != nil {
+		return apiErrorDiagnostics(err, nil)
+	}
+
+	if grants := grantsIntersection(grants, existing); len(grants) > 0 {
+		if err := updateGrants(ctx, client, &api.UpdateGrantsRequest{GrantsToRemove: grants}); err != nil {
+			return apiErrorDiagnostics(err, nil)
+		}
+	}
+
+	d.SetId("")
+
+	var diags diag.Diagnostics
+	return diags
+}
+
+func resourceResourceGrantsImport(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	cluster, namespace, _ := strings.Cut(d.Id(), ".")
+
+	if err := d.Set("cluster", cluster); err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("namespace", namespace); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandResourceGrants returns a grant request on resource for each grant configuration.
+func expandResourceGrants(resource string, grants any) ([]api.GrantRequest, error) {
+	var requests []api.GrantRequest
+
+	for _, item := range grants.(*schema.Set).List() {
+		grant := item.(map[string]interface{})
+
+		userID, groupID := grant["user_id"].(string), grant["group_id"].(string)
+		if (userID == "") == (groupID == "") {
+			return nil, fmt.Errorf("exactly one of `user_id,group_id` must be specified")
+		}
+
+		request := api.GrantRequest{
+			Resource:  resource,
+			Privilege: grant["role"].(string),
+		}
+
+		if userID != "" {
+			id, err := uid.Parse([]byte(userID))
+			if err != nil {
+				return nil, err
+			}
+
+			request.User = id
+		}
+
+		if groupID != "" {
+			id, err := uid.Parse([]byte(groupID))
+			if err != nil {
+				return nil, err
+			}
+
+			request.Group = id
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// setResourceGrants adds and removes grants so the grants on resource are exactly grants.
+func setResourceGrants(ctx context.Context, client *api.Client, resource string, grants []api.GrantRequest) error {
+	existing, err := listGrantsForResource(ctx, client, resource)
+	if err != nil {
+		return err
+	}
+
+	request := &api.UpdateGrantsRequest{
+		GrantsToAdd:    grantsDifference(grants, existing),
+		GrantsToRemove: grantsDifference(existing, grants),
+	}
+
+	if len(request.GrantsToAdd) == 0 && len(request.GrantsToRemove) == 0 {
+		return nil
+	}
+
+	return updateGrants(ctx, client, request)
+}
+
+func listGrantsForResource(ctx context.Context, client *api.Client, resource string) ([]api.GrantRequest, error) {
+	items, err := listAll(func(page int) (*api.ListResponse[api.Grant], error) {
+		return client.ListGrants(ctx, api.ListGrantsRequest{
+			Resource: resource,
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	grants := make([]api.GrantRequest, 0, len(items))
+	for _, item := range items {
+		grants = append(grants, api.GrantRequest{User: item.User, Group: item.Group, Resource: item.Resource, Privilege: item.Privilege})
+	}
+
+	return grants, nil
+}
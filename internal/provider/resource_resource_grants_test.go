@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gotest.tools/v3/assert"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+func TestAccResourceResourceGrants(t *testing.T) {
+	email := randomEmail()
+	name := randomName()
+
+	cluster := randomName("cluster")
+
+	resourceName := fmt.Sprintf("infra_resource_grants.%s", t.Name())
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceResourceGrants(t, email, name, cluster, fmt.Sprintf(`
+	grant {
+		user_id = infra_user.%[1]s.id
+		role = "view"
+	}`, t.Name())),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", cluster),
+					resource.TestCheckResourceAttr(resourceName, "grant.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceResourceGrants(t, email, name, cluster, fmt.Sprintf(`
+	grant {
+		user_id = infra_user.%[1]s.id
+		role = "edit"
+	}
+
+	grant {
+		group_id = infra_group.%[1]s.id
+		role = "view"
+	}`, t.Name())),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "grant.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceResourceGrants(t *testing.T, email, name, cluster, grants string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "%[1]s" {
+	name = "%[2]s"
+}
+
+resource "infra_group" "%[1]s" {
+	name = "%[3]s"
+}
+
+resource "infra_resource_grants" "%[1]s" {
+	cluster = "%[4]s"
+%[5]s
+}`, t.Name(), email, name, cluster, grants)
+}
+
+func TestResourceResourceGrantsDelete(t *testing.T) {
+	declared := api.Grant{ID: uid.New(), User: uid.New(), Privilege: "view", Resource: "production.default"}
+	undeclared := api.Grant{ID: uid.New(), User: uid.New(), Privilege: "edit", Resource: "production.default"}
+
+	var removed []api.GrantRequest
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, r.URL.Query().Get("resource"), "production.default")
+
+			// the undeclared grant is on the second page
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			items := []api.Grant{declared}
+			if page == 2 {
+				items = []api.Grant{undeclared}
+			}
+
+			testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Grant]{
+				Count:              1,
+				Items:              items,
+				PaginationResponse: api.PaginationResponse{Page: page, TotalPages: 2},
+			})
+		case http.MethodPatch:
+			var request api.UpdateGrantsRequest
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, len(request.GrantsToAdd), 0)
+
+			removed = append(removed, request.GrantsToRemove...)
+			testWriteJSON(t, w, http.StatusOK, struct{}{})
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceResourceGrants().Schema, map[string]interface{}{
+		"cluster":   "production",
+		"namespace": "default",
+		"grant": []interface{}{
+			map[string]interface{}{"user_id": declared.User.String(), "role": "view"},
+			// removed from Infra outside of terraform
+			map[string]interface{}{"user_id": uid.New().String(), "role": "view"},
+		},
+	})
+	d.SetId("production.default")
+
+	diags := resourceResourceGrantsDelete(context.Background(), d, testProviderMeta(t, mux))
+	assert.Assert(t, !diags.HasError(), "%v", diags)
+	assert.Equal(t, d.Id(), "")
+
+	expected := []api.GrantRequest{{User: declared.User, Privilege: "view", Resource: "production.default"}}
+	assert.DeepEqual(t, removed, expected)
+}
@@ -3,27 +3,62 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/infrahq/infra/api"
 	"github.com/infrahq/infra/uid"
 )
 
+var userAPIFields = apiFieldPaths{
+	"name":        cty.GetAttrPath("name"),
+	"password":    cty.GetAttrPath("password"),
+	"oldPassword": cty.GetAttrPath("old_password"),
+}
+
 func resourceUser() *schema.Resource {
 	return &schema.Resource{
-		Description: "Infra user resource creates a user with a specified name. The name must be an email address.",
+		Description: `Infra user resource creates a user with a specified name. The name must be an email address.
+
+~> Deleting a system identity, or a user with the last Infra admin grant, is refused when the user is destroyed, which is checked during apply rather than in the plan. Set ` + "`lifecycle { prevent_destroy = true }`" + ` on users which must not be destroyed to stop such plans.`,
 
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
 		UpdateContext: resourceUserUpdate,
 		DeleteContext: resourceUserDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: resourceUserSystemIdentityCustomizeDiff,
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceUserV0().CoreConfigSchema().ImpliedType(),
+				// passwords are kept as is, since a generated password cannot be told
+				// apart from a configured one here. A configured password is planned to
+				// change to its hash and is hashed by the next apply.
+				Upgrade: setStateDefaults(map[string]interface{}{
+					"deletion_policy":       "delete",
+					"allow_system_identity": false,
+				}),
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Description: "The user's unique identifier.",
@@ -38,23 +73,159 @@ func resourceUser() *schema.Resource {
 				ValidateDiagFunc: validateStringIsEmail(),
 			},
 			"password": {
-				Description:      "The user's password. This password is one-time use and must be changed before the account can be used. If omitted, a password will be randomly generated. Note: this field will be empty for an imported user.",
+				Description:      "The user's password. This password is one-time use and must be changed before the account can be used. If omitted, a password will be randomly generated. A configured password is stored in the state as a SHA-256 hash, a generated password is stored as is. Note: this field will be empty for an imported user.",
 				Type:             schema.TypeString,
 				Computed:         true,
 				Optional:         true,
 				Sensitive:        true,
+				StateFunc:        hashStateFunc,
+				ValidateDiagFunc: stringMinLength(8),
+				ConflictsWith: []string{
+					"password_wo",
+				},
+			},
+			"password_wo": {
+				Description:      "The user's one-time password. Unlike `password`, this value is not stored in the Terraform state. It is only sent to Infra when the user is created or `password_wo_version` changes.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
 				ValidateDiagFunc: stringMinLength(8),
+				StateFunc:        writeOnlyStateFunc,
+				ConflictsWith: []string{
+					"password",
+				},
+			},
+			"old_password": {
+				Description: "The user's current password. Infra requires it to change the password of the user of the provider's access key, so it must be set with `password`, `password_wo` or `reset_password` when managing that user. Like `password_wo`, this value is not stored in the Terraform state.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				StateFunc:   writeOnlyStateFunc,
+			},
+			"password_wo_version": {
+				Description: "Change this value to set the user's password to the value of `password_wo`. Since `password_wo` is not stored in the state, changes to it are not detected.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				RequiredWith: []string{
+					"password_wo",
+				},
+			},
+			"reset_password": {
+				Description: "Arbitrary map of values that, when changed, will reset the user's password to a new randomly generated one-time password. The new password is stored in `password`.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				ConflictsWith: []string{
+					"password", "password_wo",
+				},
+			},
+			"deletion_policy": {
+				Description: "What happens to the user when this resource is destroyed. `delete` deletes the user. `abandon` removes the user from the Terraform state but leaves it unchanged in Infra. `remove_grants_only` removes the user's grants and group memberships but does not delete the user.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "delete",
+				ValidateDiagFunc: validation.ToDiagFunc(
+					validation.StringInSlice([]string{"delete", "abandon", "remove_grants_only"}, false),
+				),
+			},
+			"adopt_existing": {
+				Description: "Manage an existing user with the same name instead of failing when the user already exists. The existing user's password is only changed if `password` or `password_wo` is set. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"allow_system_identity": {
+				Description: "Allow this resource to manage a system identity, such as the `connector` user. System identities are used by Infra itself and changing them may break Infra.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"created": {
+				Description: "The date-time when the user was created.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"updated": {
+				Description: "The date-time when the user was last updated.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"last_seen_at": {
+				Description: "The date-time when the user was last seen. Empty if the user has never logged in.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ssh_login_name": {
+				Description: "The username used to log in to SSH destinations.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"provider_names": {
+				Description: "The names of the identity providers the user has logged in with.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"groups": {
+				Description: "The names of the groups the user is a member of.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"public_keys": {
+				Description: "The user's SSH public keys.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "The ID of the public key.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": {
+							Description: "The name of the public key.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"key_type": {
+							Description: "The type of the public key, e.g. `ssh-ed25519`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"public_key": {
+							Description: "The base64 encoded public key.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"fingerprint": {
+							Description: "The SHA256 fingerprint of the public key.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"created": {
+							Description: "The date-time when the public key was added.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
 			},
 		},
 	}
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
-	user, err := client.CreateUser(ctx, &api.CreateUserRequest{Name: d.Get("name").(string)})
+	user, err := createUser(ctx, client, d)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, userAPIFields)
 	}
 
 	if password := d.Get("password").(string); password != "" {
@@ -64,7 +235,16 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m any) diag
 		}
 
 		if _, err := client.UpdateUser(ctx, &request); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+	} else if password := writeOnlyString(d, "password_wo"); password != "" {
+		request := api.UpdateUserRequest{
+			ID:       user.ID,
+			Password: password,
+		}
+
+		if _, err := client.UpdateUser(ctx, &request); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
 		}
 	} else {
 		if err := d.Set("password", user.OneTimePassword); err != nil {
@@ -76,8 +256,30 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m any) diag
 	return resourceUserRead(ctx, d, m)
 }
 
+// createUser creates a user. If the user already exists and the resource sets
+// `adopt_existing`, the existing user is returned instead, without a one-time password.
+func createUser(ctx context.Context, client *api.Client, d *schema.ResourceData) (*api.CreateUserResponse, error) {
+	name := d.Get("name").(string)
+
+	response, err := client.CreateUser(ctx, &api.CreateUserRequest{Name: name})
+	if err != nil {
+		if api.ErrorStatusCode(err) == http.StatusConflict && d.Get("adopt_existing").(bool) {
+			user, err := userFromEmail(ctx, client, name)
+			if err != nil {
+				return nil, err
+			}
+
+			return &api.CreateUserResponse{ID: user.ID, Name: user.Name}, nil
+		}
+
+		return nil, err
+	}
+
+	return response, nil
+}
+
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
@@ -86,34 +288,104 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, m any) diag.D
 
 	user, err := client.GetUser(ctx, id)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, userAPIFields)
+	}
+
+	var diags diag.Diagnostics
+	if isSystemIdentity(user) && !d.Get("allow_system_identity").(bool) {
+		diags = append(diags, systemIdentityWarning(user))
 	}
 
 	if err := d.Set("name", user.Name); err != nil {
 		return diag.FromErr(err)
 	}
 
-	var diags diag.Diagnostics
+	if err := d.Set("created", FormatTime(user.Created)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("updated", FormatTime(user.Updated)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("last_seen_at", FormatTime(user.LastSeenAt)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("ssh_login_name", user.SSHLoginName); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("provider_names", user.ProviderNames); err != nil {
+		return diag.FromErr(err)
+	}
+
+	publicKeys := make([]map[string]interface{}, 0, len(user.PublicKeys))
+	for _, item := range user.PublicKeys {
+		publicKey := make(map[string]interface{})
+		publicKey["id"] = item.ID.String()
+		publicKey["name"] = item.Name
+		publicKey["key_type"] = item.KeyType
+		publicKey["public_key"] = item.PublicKey
+		publicKey["fingerprint"] = item.Fingerprint
+		publicKey["created"] = FormatTime(item.Created)
+
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	if err := d.Set("public_keys", publicKeys); err != nil {
+		return diag.FromErr(err)
+	}
+
+	groups, err := userGroups(ctx, client, user.ID)
+	if err != nil {
+		return apiErrorDiagnostics(err, userAPIFields)
+	}
+
+	if err := d.Set("groups", groups); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return diags
 }
 
 func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if d.HasChange("password") {
-		oldPassword, newPassword := d.GetChange("password")
-		request := api.UpdateUserRequest{
-			ID:          id,
-			OldPassword: oldPassword.(string),
-			Password:    newPassword.(string),
+	switch {
+	case d.HasChange("reset_password"):
+		settings, err := client.GetSettings(ctx)
+		if err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
 		}
 
-		if _, err := client.UpdateUser(ctx, &request); err != nil {
+		password, err := generatePassword(settings.PasswordRequirements)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := updateUserPassword(ctx, client, id, writeOnlyString(d, "old_password"), password); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+
+		if err := d.Set("password", password); err != nil {
+			return diag.FromErr(err)
+		}
+	case d.HasChange("password") && d.Get("password").(string) != "":
+		if err := updateUserPassword(ctx, client, id, writeOnlyString(d, "old_password"), d.Get("password").(string)); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+	case d.HasChange("password_wo_version") && writeOnlyString(d, "password_wo") != "":
+		if err := updateUserPassword(ctx, client, id, writeOnlyString(d, "old_password"), writeOnlyString(d, "password_wo")); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+
+		if err := d.Set("password", ""); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -121,16 +393,65 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m any) diag
 	return resourceUserRead(ctx, d, m)
 }
 
+// updateUserPassword sets a new one-time password for the user. Infra admins can reset the
+// password of other users without the old password, so the old password is only sent, and
+// required, when the user is the user of the provider's access key. The state only has a
+// hash of a configured password, so the old password comes from `old_password`.
+func updateUserPassword(ctx context.Context, client *api.Client, id uid.ID, oldPassword, password string) error {
+	request := api.UpdateUserRequest{
+		ID:       id,
+		Password: password,
+	}
+
+	self, err := client.GetUserSelf(ctx)
+	if err != nil {
+		return err
+	}
+
+	if self.ID == id {
+		if oldPassword == "" {
+			return errOldPasswordRequired
+		}
+
+		request.OldPassword = oldPassword
+	}
+
+	_, err = client.UpdateUser(ctx, &request)
+	return err
+}
+
+var errOldPasswordRequired = fmt.Errorf("`old_password` is required to change the password of the user of the provider's access key")
+
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*api.Client)
+	client := m.(*providerMeta).client
 
 	id, err := ParseID(d, "id")
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err := client.DeleteUser(ctx, id); err != nil {
-		return diag.FromErr(err)
+	switch d.Get("deletion_policy").(string) {
+	case "abandon":
+	case "remove_grants_only":
+		if err := checkSystemIdentity(ctx, client, d, id); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+
+		if err := removeUserAccess(ctx, client, id); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+	default:
+		if err := checkSystemIdentity(ctx, client, d, id); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+
+		if err := checkNotLastInfraAdminUser(ctx, client, id); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
+
+		if err := client.DeleteUser(ctx, id); err != nil {
+			return apiErrorDiagnostics(err, userAPIFields)
+		}
 	}
 
 	d.SetId("")
@@ -139,14 +460,160 @@ func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m any) diag
 	return diags
 }
 
-func userFromIDOrEmail(ctx context.Context, client *api.Client, d *schema.ResourceData, id, email string) (*api.User, error) {
-	if s := d.Get(id).(string); s != "" {
-		userID, err := uid.Parse([]byte(s))
-		if err != nil {
-			return nil, err
+// isSystemIdentity returns true if the user is an identity used by Infra itself.
+func isSystemIdentity(user *api.User) bool {
+	return user.Name == "connector"
+}
+
+func systemIdentityWarning(user *api.User) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Managing a system identity",
+		Detail:   fmt.Sprintf("%s is a system identity used by Infra. Changing it may break Infra. Set `allow_system_identity = true` to manage it and silence this warning.", user.Name),
+	}
+}
+
+// resourceUserSystemIdentityCustomizeDiff refuses to change the password of a system
+// identity unless `allow_system_identity` is set.
+func resourceUserSystemIdentityCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m any) error {
+	if d.Id() == "" || d.Get("allow_system_identity").(bool) {
+		return nil
+	}
+
+	// the state has the hash of a configured password
+	oldPassword, newPassword := d.GetChange("password")
+	passwordChanged := oldPassword != hashStateFunc(newPassword) && oldPassword != newPassword
+
+	if !passwordChanged && !d.HasChanges("password_wo_version", "reset_password") {
+		return nil
+	}
+
+	id, err := uid.Parse([]byte(d.Id()))
+	if err != nil {
+		return err
+	}
+
+	user, err := m.(*providerMeta).client.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if isSystemIdentity(user) {
+		return fmt.Errorf("refusing to modify system identity %s: set `allow_system_identity = true` to override", user.Name)
+	}
+
+	return nil
+}
+
+// checkSystemIdentity returns an error if the user is a system identity and the resource
+// does not set `allow_system_identity`.
+func checkSystemIdentity(ctx context.Context, client *api.Client, d *schema.ResourceData, userID uid.ID) error {
+	if d.Get("allow_system_identity").(bool) {
+		return nil
+	}
+
+	user, err := client.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if isSystemIdentity(user) {
+		return fmt.Errorf("refusing to modify system identity %s: set `allow_system_identity = true` to override", user.Name)
+	}
+
+	return nil
+}
+
+// checkNotLastInfraAdminUser returns an error if the user's grants are the only Infra
+// admin grants. Deleting the user or removing their grants would leave no one able to
+// manage Infra.
+func checkNotLastInfraAdminUser(ctx context.Context, client *api.Client, userID uid.ID) error {
+	grants, err := listInfraAdminGrants(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	for _, grant := range grants {
+		if grant.User != userID {
+			return nil
+		}
+	}
+
+	if len(grants) > 0 {
+		return errLastInfraAdminGrant
+	}
+
+	return nil
+}
+
+// removeUserAccess removes all of the user's grants and group memberships. It refuses to
+// remove the user's grants when they are the last Infra admin grants.
+func removeUserAccess(ctx context.Context, client *api.Client, userID uid.ID) error {
+	if err := checkNotLastInfraAdminUser(ctx, client, userID); err != nil {
+		return err
+	}
+
+	grants, err := listGrantsForSubjects(ctx, client, []api.GrantRequest{{User: userID}})
+	if err != nil {
+		return err
+	}
+
+	if len(grants) > 0 {
+		if err := updateGrants(ctx, client, &api.UpdateGrantsRequest{GrantsToRemove: grants}); err != nil {
+			return err
+		}
+	}
+
+	groups, err := listUserGroups(ctx, client, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		request := &api.UpdateUsersInGroupRequest{
+			GroupID:         group.ID,
+			UserIDsToRemove: []uid.ID{userID},
+		}
+
+		if err := client.UpdateUsersInGroup(ctx, request); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// listUserGroups returns every group where the user is a member.
+func listUserGroups(ctx context.Context, client *api.Client, userID uid.ID) ([]api.Group, error) {
+	return listAll(func(page int) (*api.ListResponse[api.Group], error) {
+		return client.ListGroups(ctx, api.ListGroupsRequest{
+			UserID: userID,
+			PaginationRequest: api.PaginationRequest{
+				Page:  page,
+				Limit: 1000,
+			},
+		})
+	})
+}
+
+// userGroups returns the names of the groups where the user is a member.
+func userGroups(ctx context.Context, client *api.Client, userID uid.ID) ([]string, error) {
+	groups, err := listUserGroups(ctx, client, userID)
+	if err != nil {
+		return nil, err
+	}
 
-		return client.GetUser(ctx, userID)
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+
+	return names, nil
+}
+
+func userFromIDOrEmail(ctx context.Context, client *api.Client, d *schema.ResourceData, id, email string) (*api.User, error) {
+	if s := d.Get(id).(string); s != "" {
+		return userFromID(ctx, client, s)
 	}
 
 	if s := d.Get(email).(string); s != "" {
@@ -156,6 +623,15 @@ func userFromIDOrEmail(ctx context.Context, client *api.Client, d *schema.Resour
 	return nil, fmt.Errorf("one of `%s,%s` must be specified", id, email)
 }
 
+func userFromID(ctx context.Context, client *api.Client, id string) (*api.User, error) {
+	userID, err := uid.Parse([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetUser(ctx, userID)
+}
+
 func userFromEmail(ctx context.Context, client *api.Client, email string) (*api.User, error) {
 	request := api.ListUsersRequest{
 		Name:       email,
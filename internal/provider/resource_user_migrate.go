@@ -0,0 +1,18 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceUserV0 is the schema of infra_user before attributes with defaults were added
+// and before a configured password was stored as a hash. Only the types are needed to
+// decode existing state.
+func resourceUserV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id":       {Type: schema.TypeString, Computed: true},
+			"name":     {Type: schema.TypeString, Required: true},
+			"password": {Type: schema.TypeString, Optional: true, Computed: true, Sensitive: true},
+		},
+	}
+}
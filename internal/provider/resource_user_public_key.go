@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/infrahq/infra/api"
+	"github.com/infrahq/infra/uid"
+)
+
+var userPublicKeyAPIFields = apiFieldPaths{
+	"name":      cty.GetAttrPath("name"),
+	"publicKey": cty.GetAttrPath("public_key"),
+}
+
+func resourceUserPublicKey() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides an SSH public key for an Infra user. Public keys are used to authenticate to SSH destinations.
+
+~> The Infra API only supports adding public keys for the user of the provider's access key and does not support removing public keys. Destroying this resource removes it from the Terraform state but the key remains active in Infra.`,
+
+		CreateContext: resourceUserPublicKeyCreate,
+		ReadContext:   resourceUserPublicKeyRead,
+		DeleteContext: resourceUserPublicKeyDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUserPublicKeyImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The public key's unique identifier.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"user_id": {
+				Description:      "The ID of the user.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateStringIsID(),
+				ExactlyOneOf: []string{
+					"user_id", "user_name",
+				},
+			},
+			"user_name": {
+				Description:      "The email of the user.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateStringIsEmail(),
+				ExactlyOneOf: []string{
+					"user_id", "user_name",
+				},
+			},
+			"name": {
+				Description:      "The public key's name, often the name of the device which created the key. Names may include letters (uppercase and lowercase), numbers, underscores `_`, hyphens `-`, and periods `.`.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateStringIsName(),
+			},
+			"public_key": {
+				Description:      "The public key as it would appear in an authorized_keys file, e.g. `ssh-ed25519 AAAA... alice@example.com`.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateStringIsAuthorizedKey(),
+				DiffSuppressFunc: AuthorizedKeyDiffSuppressFunc(),
+			},
+			"key_type": {
+				Description: "The type of the public key, e.g. `ssh-ed25519`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"fingerprint": {
+				Description: "The SHA256 fingerprint of the public key.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"created": {
+				Description: "The date-time when the public key was added.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceUserPublicKeyCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	user, err := userFromIDOrEmail(ctx, client, d, "user_id", "user_name")
+	if err != nil {
+		return apiErrorDiagnostics(err, userPublicKeyAPIFields)
+	}
+
+	self, err := client.GetUserSelf(ctx)
+	if err != nil {
+		return apiErrorDiagnostics(err, userPublicKeyAPIFields)
+	}
+
+	if user.ID != self.ID {
+		return diag.Errorf("public keys can only be added for the user of the provider's access key: %s", self.Name)
+	}
+
+	request := &api.AddUserPublicKeyRequest{
+		Name:      d.Get("name").(string),
+		PublicKey: d.Get("public_key").(string),
+	}
+
+	publicKey, err := client.AddUserPublicKey(ctx, request)
+	if err != nil {
+		return apiErrorDiagnostics(err, userPublicKeyAPIFields)
+	}
+
+	if err := d.Set("user_id", user.ID.String()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(publicKey.ID.String())
+	return resourceUserPublicKeyRead(ctx, d, m)
+}
+
+func resourceUserPublicKeyRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	userID, err := ParseID(d, "user_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user, err := client.GetUser(ctx, userID)
+	if err != nil {
+		return apiErrorDiagnostics(err, userPublicKeyAPIFields)
+	}
+
+	var publicKey *api.UserPublicKey
+	for i := range user.PublicKeys {
+		if user.PublicKeys[i].ID.String() == d.Id() {
+			publicKey = &user.PublicKeys[i]
+			break
+		}
+	}
+
+	// the public key may have been removed with the user's other credentials
+	if publicKey == nil {
+		d.SetId("")
+
+		var diags diag.Diagnostics
+		return diags
+	}
+
+	if err := d.Set("user_name", user.Name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("name", publicKey.Name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("public_key", fmt.Sprintf("%s %s", publicKey.KeyType, publicKey.PublicKey)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("key_type", publicKey.KeyType); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("fingerprint", publicKey.Fingerprint); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("created", FormatTime(publicKey.Created)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	return diags
+}
+
+func resourceUserPublicKeyDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	d.SetId("")
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Public key was not removed from Infra",
+			Detail:   fmt.Sprintf("The Infra API does not support removing public keys. The public key %s has been removed from the Terraform state but remains active.", d.Get("fingerprint")),
+		},
+	}
+}
+
+// resourceUserPublicKeyImport imports a public key using the ID format `<user_id>/<public_key_id>`.
+func resourceUserPublicKeyImport(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	userID, publicKeyID, ok := strings.Cut(d.Id(), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid ID %q, expected <user_id>/<public_key_id>", d.Id())
+	}
+
+	for _, s := range []string{userID, publicKeyID} {
+		if _, err := uid.Parse([]byte(s)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.Set("user_id", userID); err != nil {
+		return nil, err
+	}
+
+	d.SetId(publicKeyID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// AuthorizedKeyDiffSuppressFunc suppresses differences between public keys which only
+// differ by comment or options.
+func AuthorizedKeyDiffSuppressFunc() schema.SchemaDiffSuppressFunc {
+	return func(k, oldValue, newValue string, d *schema.ResourceData) bool {
+		oldKey, err := ParseAuthorizedKey(oldValue)
+		if err != nil {
+			return false
+		}
+
+		newKey, err := ParseAuthorizedKey(newValue)
+		if err != nil {
+			return false
+		}
+
+		return bytes.Equal(oldKey.Marshal(), newKey.Marshal())
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAuthorizedKeyDiffSuppressFunc(t *testing.T) {
+	key := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEHq8q6XONzwuEnzItsEeF+yilBPmQ6jlQ3lGWzvM0rf"
+
+	cases := map[string]struct {
+		oldValue string
+		newValue string
+		expected bool
+	}{
+		"same": {
+			oldValue: key,
+			newValue: key,
+			expected: true,
+		},
+		"comment": {
+			oldValue: key,
+			newValue: key + " alice@example.com",
+			expected: true,
+		},
+		"different": {
+			oldValue: key,
+			newValue: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINYKGtApU56jMC4pEFi0kKlL+5QdszvOMg91gFeA0IHM",
+		},
+		"invalid": {
+			oldValue: key,
+			newValue: "ssh-ed25519",
+		},
+	}
+
+	fn := AuthorizedKeyDiffSuppressFunc()
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, fn("public_key", tc.oldValue, tc.newValue, nil), tc.expected)
+		})
+	}
+}
@@ -1,12 +1,21 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
 
+	"github.com/infrahq/infra/api"
 	"github.com/infrahq/infra/uid"
 )
 
@@ -19,14 +28,19 @@ func TestAccResourceUser(t *testing.T) {
 	resourceName := fmt.Sprintf("infra_user.%s", t.Name())
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          testAccPreCheck(t),
-		ProviderFactories: testAccProviders(t),
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceUser(t, email1),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id1)),
 					resource.TestCheckResourceAttr(resourceName, "name", email1),
+					resource.TestCheckResourceAttrSet(resourceName, "created"),
+					resource.TestCheckResourceAttrSet(resourceName, "updated"),
+					resource.TestCheckResourceAttr(resourceName, "last_seen_at", ""),
+					resource.TestCheckResourceAttr(resourceName, "groups.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "public_keys.#", "0"),
 				),
 			},
 			{
@@ -34,7 +48,7 @@ func TestAccResourceUser(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id1)),
 					resource.TestCheckResourceAttr(resourceName, "name", email1),
-					resource.TestCheckResourceAttr(resourceName, "password", "password"),
+					resource.TestCheckResourceAttr(resourceName, "password", hashStateFunc("password")),
 				),
 			},
 			{
@@ -49,6 +63,99 @@ func TestAccResourceUser(t *testing.T) {
 	})
 }
 
+func TestAccResourceUser_passwordWriteOnly(t *testing.T) {
+	var id1, id2 uid.ID
+
+	email := randomEmail()
+
+	resourceName := fmt.Sprintf("infra_user.%s", t.Name())
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUser_passwordWriteOnly(t, email, "password", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id1)),
+					resource.TestCheckResourceAttr(resourceName, "password", ""),
+					resource.TestCheckResourceAttr(resourceName, "password_wo", ""),
+				),
+			},
+			{
+				Config: testAccResourceUser_passwordWriteOnly(t, email, "password2", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id2)),
+					resource.TestCheckResourceAttr(resourceName, "password_wo", ""),
+					resource.TestCheckResourceAttr(resourceName, "password_wo_version", "2"),
+					testAccCheckIDUnchanged(&id1, &id2),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUser_resetPassword(t *testing.T) {
+	var id1, id2 uid.ID
+
+	email := randomEmail()
+
+	resourceName := fmt.Sprintf("infra_user.%s", t.Name())
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUser_resetPassword(t, email, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id1)),
+					resource.TestCheckResourceAttrSet(resourceName, "password"),
+				),
+			},
+			{
+				Config: testAccResourceUser_resetPassword(t, email, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", testCheckResourceAttrWithID(&id2)),
+					resource.TestCheckResourceAttrSet(resourceName, "password"),
+					resource.TestCheckResourceAttr(resourceName, "reset_password.rotation", "2"),
+					testAccCheckIDUnchanged(&id1, &id2),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUser_deletionPolicyRemoveGrantsOnly(t *testing.T) {
+	email := randomEmail()
+	name := randomName()
+
+	cluster := randomName("cluster")
+
+	resourceName := fmt.Sprintf("infra_user.%s", t.Name())
+	dataSourceName := fmt.Sprintf("data.infra_users.%s", t.Name())
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ProtoV5ProviderFactories: testAccProviders(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUser_deletionPolicy(t, email, name, cluster, "remove_grants_only"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "deletion_policy", "remove_grants_only"),
+				),
+			},
+			{
+				Config: testAccResourceUser_deletionPolicyRemoved(t, email),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "users.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "users.0.groups.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func randomEmail() string {
 	return fmt.Sprintf("%s@example.com", randomName())
 }
@@ -68,6 +175,65 @@ resource "infra_user" "%[1]s" {
 }`, t.Name(), email, password)
 }
 
+func testAccResourceUser_passwordWriteOnly(t *testing.T, email, password, version string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "%[1]s" {
+	name = "%[2]s"
+	password_wo = "%[3]s"
+	password_wo_version = "%[4]s"
+}`, t.Name(), email, password, version)
+}
+
+func testAccResourceUser_resetPassword(t *testing.T, email, rotation string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "%[1]s" {
+	name = "%[2]s"
+
+	reset_password = {
+		rotation = "%[3]s"
+	}
+}`, t.Name(), email, rotation)
+}
+
+func testAccResourceUser_deletionPolicy(t *testing.T, email, name, cluster, policy string) string {
+	return fmt.Sprintf(`
+resource "infra_user" "%[1]s" {
+	name = "%[2]s"
+	deletion_policy = "%[5]s"
+}
+
+resource "infra_group" "%[1]s" {
+	name = "%[3]s"
+}
+
+resource "infra_group_membership" "%[1]s" {
+	user_id = infra_user.%[1]s.id
+	group_id = infra_group.%[1]s.id
+}
+
+resource "infra_grant" "%[1]s" {
+	user_id = infra_user.%[1]s.id
+
+	kubernetes {
+		role = "view"
+		cluster = "%[4]s"
+	}
+}`, t.Name(), email, name, cluster, policy)
+}
+
+// testAccResourceUser_deletionPolicyRemoved removes the user from the configuration and
+// reads it back to check the user was not deleted.
+func testAccResourceUser_deletionPolicyRemoved(t *testing.T, email string) string {
+	return fmt.Sprintf(`
+data "infra_users" "%[1]s" {
+	filter {
+		name = "%[2]s"
+	}
+
+	include_groups = true
+}`, t.Name(), email)
+}
+
 func testCheckResourceAttrWithID(out *uid.ID) func(s string) error {
 	return func(s string) error {
 		id, err := uid.Parse([]byte(s))
@@ -99,3 +265,492 @@ func testAccCheckIDUnchanged(id1, id2 *uid.ID) resource.TestCheckFunc {
 		return fmt.Errorf("resource should not have been recreated")
 	}
 }
+
+func TestResourceUserCreate_adoptExisting(t *testing.T) {
+	user := api.User{ID: uid.New(), Name: "alice@example.com"}
+
+	var passwords []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			testWriteJSON(t, w, http.StatusConflict, api.Error{Code: http.StatusConflict, Message: "a user with that name already exists"})
+		case http.MethodGet:
+			assert.Equal(t, r.URL.Query().Get("name"), user.Name)
+			testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.User]{Count: 1, Items: []api.User{user}})
+		}
+	})
+	mux.HandleFunc("/api/users/"+user.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var request api.UpdateUserRequest
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+			passwords = append(passwords, request.Password)
+		}
+
+		testWriteJSON(t, w, http.StatusOK, user)
+	})
+	mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Group]{})
+	})
+
+	meta := testProviderMeta(t, mux)
+
+	t.Run("adopt", func(t *testing.T) {
+		passwords = nil
+
+		d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+			"name":           user.Name,
+			"adopt_existing": true,
+		})
+
+		diags := resourceUserCreate(context.Background(), d, meta)
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+		assert.Equal(t, d.Id(), user.ID.String())
+		assert.Equal(t, d.Get("password"), "")
+		assert.Equal(t, len(passwords), 0)
+	})
+
+	t.Run("adopt with password", func(t *testing.T) {
+		passwords = nil
+
+		d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+			"name":           user.Name,
+			"password":       "password123",
+			"adopt_existing": true,
+		})
+
+		diags := resourceUserCreate(context.Background(), d, meta)
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+		assert.Equal(t, d.Id(), user.ID.String())
+		assert.DeepEqual(t, passwords, []string{"password123"})
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+			"name": user.Name,
+		})
+
+		diags := resourceUserCreate(context.Background(), d, meta)
+		assert.Assert(t, diags.HasError())
+		assert.Equal(t, diags[0].Summary, "a user with that name already exists")
+		assert.Equal(t, d.Id(), "")
+	})
+}
+
+func TestResourceUser_passwordWriteOnly(t *testing.T) {
+	user := api.User{ID: uid.New(), Name: "alice@example.com"}
+	self := api.User{ID: uid.New(), Name: "admin@example.com"}
+
+	var requests []api.UpdateUserRequest
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users/self", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, self)
+	})
+	mux.HandleFunc("/api/users/"+user.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var request api.UpdateUserRequest
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+			requests = append(requests, request)
+		}
+
+		testWriteJSON(t, w, http.StatusOK, user)
+	})
+	mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Group]{})
+	})
+
+	meta := testProviderMeta(t, mux)
+	ctx := context.Background()
+
+	r := resourceUser()
+	state := &terraform.InstanceState{
+		ID: user.ID.String(),
+		Attributes: map[string]string{
+			"id":                    user.ID.String(),
+			"name":                  user.Name,
+			"password_wo":           "",
+			"password_wo_version":   "1",
+			"deletion_policy":       "delete",
+			"allow_system_identity": "false",
+		},
+	}
+
+	t.Run("changing the password is not detected", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name":                user.Name,
+			"password_wo":         "password2",
+			"password_wo_version": "1",
+		}
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+		assert.Assert(t, diff.Attributes["password_wo"] == nil, "%v", diff.Attributes["password_wo"])
+		assert.Assert(t, diff.Attributes["password_wo_version"] == nil, "%v", diff.Attributes["password_wo_version"])
+	})
+
+	t.Run("changing the version sets the password", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name":                user.Name,
+			"password_wo":         "password2",
+			"password_wo_version": "2",
+		}
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+
+		diff.RawConfig = testRawConfig(r, config)
+
+		newState, diags := r.Apply(ctx, state, diff, meta)
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+
+		assert.DeepEqual(t, requests, []api.UpdateUserRequest{{Password: "password2"}})
+		assert.Equal(t, newState.Attributes["password_wo"], "")
+		assert.Equal(t, newState.Attributes["password_wo_version"], "2")
+	})
+}
+
+func TestResourceUser_passwordHash(t *testing.T) {
+	// the user is a system identity, so a password change would fail the plan unless
+	// allow_system_identity is set. No requests are expected.
+	meta := testProviderMeta(t, http.NewServeMux())
+
+	r := resourceUser()
+	state := &terraform.InstanceState{
+		ID: uid.New().String(),
+		Attributes: map[string]string{
+			"id":                    "2Ygu6jvYUs",
+			"name":                  "connector",
+			"password":              hashStateFunc("password123"),
+			"deletion_policy":       "delete",
+			"allow_system_identity": "false",
+		},
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name":     "connector",
+			"password": "password123",
+		}
+
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+		assert.Assert(t, diff.Attributes["password"] == nil, "%v", diff.Attributes["password"])
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name":                  "connector",
+			"password":              "password456",
+			"allow_system_identity": true,
+		}
+
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+		assert.Equal(t, diff.Attributes["password"].New, hashStateFunc("password456"))
+	})
+}
+
+func TestResourceUser_oldPassword(t *testing.T) {
+	// the user is the user of the provider's access key
+	user := api.User{ID: uid.New(), Name: "admin@example.com"}
+
+	var requests []api.UpdateUserRequest
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users/self", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, user)
+	})
+	mux.HandleFunc("/api/users/"+user.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var request api.UpdateUserRequest
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+			requests = append(requests, request)
+		}
+
+		testWriteJSON(t, w, http.StatusOK, user)
+	})
+	mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, testListResponse[api.Group]())
+	})
+
+	meta := testProviderMeta(t, mux)
+	ctx := context.Background()
+
+	r := resourceUser()
+	state := &terraform.InstanceState{
+		ID: user.ID.String(),
+		Attributes: map[string]string{
+			"id":                    user.ID.String(),
+			"name":                  user.Name,
+			"password":              hashStateFunc("password1"),
+			"old_password":          "",
+			"deletion_policy":       "delete",
+			"allow_system_identity": "false",
+		},
+	}
+
+	apply := func(t *testing.T, config map[string]interface{}) diag.Diagnostics {
+		requests = nil
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+
+		diff.RawConfig = testRawConfig(r, config)
+
+		newState, diags := r.Apply(ctx, state, diff, meta)
+		if !diags.HasError() {
+			assert.Equal(t, newState.Attributes["old_password"], "")
+		}
+
+		return diags
+	}
+
+	t.Run("password", func(t *testing.T) {
+		diags := apply(t, map[string]interface{}{
+			"name":         user.Name,
+			"password":     "password2",
+			"old_password": "password1",
+		})
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+
+		assert.DeepEqual(t, requests, []api.UpdateUserRequest{{Password: "password2", OldPassword: "password1"}})
+	})
+
+	t.Run("password_wo", func(t *testing.T) {
+		diags := apply(t, map[string]interface{}{
+			"name":                user.Name,
+			"password_wo":         "password2",
+			"password_wo_version": "1",
+			"old_password":        "password1",
+		})
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+
+		assert.DeepEqual(t, requests, []api.UpdateUserRequest{{Password: "password2", OldPassword: "password1"}})
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		diags := apply(t, map[string]interface{}{
+			"name":     user.Name,
+			"password": "password2",
+		})
+		assert.Assert(t, diags.HasError())
+		assert.Equal(t, diags[0].Summary, errOldPasswordRequired.Error())
+
+		assert.Equal(t, len(requests), 0)
+	})
+}
+
+// TestResourceUser_upgradedPassword checks a password stored as is by an earlier version.
+// A configured password is hashed by the next apply without changing the user's password,
+// and a generated password is kept.
+func TestResourceUser_upgradedPassword(t *testing.T) {
+	user := api.User{ID: uid.New(), Name: "alice@example.com"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users/"+user.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+
+		testWriteJSON(t, w, http.StatusOK, user)
+	})
+	mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, testListResponse[api.Group]())
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	})
+
+	meta := testProviderMeta(t, mux)
+	ctx := context.Background()
+
+	r := resourceUser()
+	state := &terraform.InstanceState{
+		ID: user.ID.String(),
+		Attributes: map[string]string{
+			"id":                    user.ID.String(),
+			"name":                  user.Name,
+			"password":              "password123",
+			"deletion_policy":       "delete",
+			"allow_system_identity": "false",
+		},
+	}
+
+	t.Run("configured", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name":     user.Name,
+			"password": "password123",
+		}
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+		assert.Equal(t, diff.Attributes["password"].New, hashStateFunc("password123"))
+
+		diff.RawConfig = testRawConfig(r, config)
+
+		newState, diags := r.Apply(ctx, state, diff, meta)
+		assert.Assert(t, !diags.HasError(), "%v", diags)
+		assert.Equal(t, newState.Attributes["password"], hashStateFunc("password123"))
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name": user.Name,
+		}
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+		assert.Assert(t, diff == nil || diff.Attributes["password"] == nil, "%v", diff)
+	})
+}
+
+func TestResourceUser_resetPasswordConflicts(t *testing.T) {
+	for _, key := range []string{"password", "password_wo"} {
+		t.Run(key, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":           "alice@example.com",
+				key:              "password123",
+				"reset_password": map[string]interface{}{"rotation": "1"},
+			})
+
+			diags := resourceUser().Validate(config)
+			assert.Assert(t, diags.HasError())
+			assert.Equal(t, diags[0].Summary, "Conflicting configuration arguments")
+		})
+	}
+}
+
+func TestResourceUser_systemIdentityCustomizeDiff(t *testing.T) {
+	connector := api.User{ID: uid.New(), Name: "connector"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users/"+connector.ID.String(), func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, connector)
+	})
+
+	meta := testProviderMeta(t, mux)
+	ctx := context.Background()
+
+	r := resourceUser()
+	state := &terraform.InstanceState{
+		ID: connector.ID.String(),
+		Attributes: map[string]string{
+			"id":                    connector.ID.String(),
+			"name":                  connector.Name,
+			"deletion_policy":       "delete",
+			"allow_system_identity": "false",
+		},
+	}
+
+	t.Run("password change fails the plan", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name":     connector.Name,
+			"password": "password123",
+		}
+
+		_, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.ErrorContains(t, err, "refusing to modify system identity connector")
+	})
+
+	t.Run("allow_system_identity", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name":                  connector.Name,
+			"password":              "password123",
+			"allow_system_identity": true,
+		}
+
+		_, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+	})
+
+	t.Run("no password change", func(t *testing.T) {
+		config := map[string]interface{}{
+			"name": connector.Name,
+		}
+
+		_, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		assert.NilError(t, err)
+	})
+}
+
+func TestRemoveUserAccess_lastInfraAdmin(t *testing.T) {
+	alice := api.User{ID: uid.New(), Name: "alice@example.com"}
+
+	var removed bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			removed = true
+		}
+
+		grants := []api.Grant{{ID: uid.New(), User: alice.ID, Resource: "infra", Privilege: "admin"}}
+		testWriteJSON(t, w, http.StatusOK, api.ListResponse[api.Grant]{Count: len(grants), Items: grants})
+	})
+
+	meta := testProviderMeta(t, mux)
+
+	err := removeUserAccess(context.Background(), meta.client, alice.ID)
+	assert.ErrorIs(t, err, errLastInfraAdminGrant)
+	assert.Assert(t, !removed)
+}
+
+func TestRemoveUserAccess_groupPages(t *testing.T) {
+	alice := api.User{ID: uid.New(), Name: "alice@example.com"}
+	pages := [][]api.Group{
+		{{ID: uid.New(), Name: "developers"}},
+		{{ID: uid.New(), Name: "operators"}},
+	}
+
+	var removed []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/grants", func(w http.ResponseWriter, r *http.Request) {
+		testWriteJSON(t, w, http.StatusOK, testListResponse[api.Grant]())
+	})
+	mux.HandleFunc("/api/groups", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("userID"), alice.ID.String())
+
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		assert.NilError(t, err)
+
+		response := testListResponse(pages[page-1]...)
+		response.Page = page
+		response.TotalPages = len(pages)
+
+		testWriteJSON(t, w, http.StatusOK, response)
+	})
+	mux.HandleFunc("/api/groups/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPatch)
+		removed = append(removed, r.URL.Path)
+
+		testWriteJSON(t, w, http.StatusOK, api.EmptyResponse{})
+	})
+
+	meta := testProviderMeta(t, mux)
+
+	err := removeUserAccess(context.Background(), meta.client, alice.ID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, removed, []string{
+		"/api/groups/" + pages[0][0].ID.String() + "/users",
+		"/api/groups/" + pages[1][0].ID.String() + "/users",
+	})
+}
+
+// testRawConfig returns the configuration of the resource as the raw config value which
+// Terraform sends with a plan. Attributes which are not in values are null.
+func testRawConfig(r *schema.Resource, values map[string]interface{}) cty.Value {
+	ty := r.CoreConfigSchema().ImpliedType()
+
+	attributes := make(map[string]cty.Value)
+	for name, attributeType := range ty.AttributeTypes() {
+		attributes[name] = cty.NullVal(attributeType)
+		if s, ok := values[name].(string); ok {
+			attributes[name] = cty.StringVal(s)
+		}
+	}
+
+	return cty.ObjectVal(attributes)
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// NewServer returns a provider server which serves the SDK provider and the framework
+// provider as a single provider.
+func NewServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	servers := []func() tfprotov5.ProviderServer{
+		New().GRPCProvider,
+		func() tfprotov5.ProviderServer {
+			return frameworkServer{providerserver.NewProtocol5(NewFramework())()}
+		},
+	}
+
+	server, err := tf5muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return server.ProviderServer, nil
+}
+
+// frameworkServer drops the prepared configuration returned by the framework provider. The
+// SDK provider fills in defaults from the environment while the framework provider returns
+// the configuration unchanged, and the mux server rejects differing prepared configurations.
+type frameworkServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (s frameworkServer) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	resp, err := s.ProviderServer.PrepareProviderConfig(ctx, req)
+	if resp != nil {
+		resp.PreparedConfig = nil
+	}
+
+	return resp, err
+}
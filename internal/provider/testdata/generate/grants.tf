@@ -0,0 +1,70 @@
+# Generated by terraform-provider-infra generate. Review this configuration and run
+# "terraform plan" to import the existing objects before making any changes.
+
+resource "infra_grant" "admins_infra_admin" {
+  group_id = infra_group.admins.id
+  infra {
+    role = "admin"
+  }
+}
+
+import {
+  to = infra_grant.admins_infra_admin
+  id = "TK"
+}
+
+resource "infra_grant" "alice_example_com_infra_view" {
+  user_id = infra_user.alice_example_com.id
+  infra {
+    role = "view"
+  }
+}
+
+import {
+  to = infra_grant.alice_example_com_infra_view
+  id = "TL"
+}
+
+resource "infra_grant" "alice_example_com_bastion_connect" {
+  user_id = infra_user.alice_example_com.id
+  destination {
+    name = "bastion"
+    role = "connect"
+  }
+}
+
+import {
+  to = infra_grant.alice_example_com_bastion_connect
+  id = "TQ"
+}
+
+resource "infra_resource_grants" "production_default" {
+  cluster   = "production"
+  namespace = "default"
+  grant {
+    group_id = infra_group.developers.id
+    role     = "edit"
+  }
+  grant {
+    user_id = infra_user.bob_example_com.id
+    role    = "view"
+  }
+}
+
+import {
+  to = infra_resource_grants.production_default
+  id = "production.default"
+}
+
+resource "infra_resource_grants" "production" {
+  cluster = "production"
+  grant {
+    group_id = infra_group.admins.id
+    role     = "cluster-admin"
+  }
+}
+
+import {
+  to = infra_resource_grants.production
+  id = "production"
+}
@@ -0,0 +1,12 @@
+# Generated by terraform-provider-infra generate. Review this configuration and run
+# "terraform plan" to import the existing objects before making any changes.
+
+resource "infra_group_members" "developers" {
+  group_id = infra_group.developers.id
+  user_ids = [infra_user.alice_example_com.id, infra_user.bob_example_com.id]
+}
+
+import {
+  to = infra_group_members.developers
+  id = "Av"
+}
@@ -0,0 +1,20 @@
+# Generated by terraform-provider-infra generate. Review this configuration and run
+# "terraform plan" to import the existing objects before making any changes.
+
+resource "infra_group" "developers" {
+  name = "developers"
+}
+
+import {
+  to = infra_group.developers
+  id = "Av"
+}
+
+resource "infra_group" "admins" {
+  name = "Admins"
+}
+
+import {
+  to = infra_group.admins
+  id = "Aw"
+}
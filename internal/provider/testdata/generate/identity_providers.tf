@@ -0,0 +1,79 @@
+# Generated by terraform-provider-infra generate. Review this configuration and run
+# "terraform plan" to import the existing objects before making any changes.
+
+resource "infra_identity_provider" "okta" {
+  name          = "okta"
+  client_id     = "okta-client"
+  client_secret = var.okta_client_secret
+  okta {
+    issuer = "https://example.okta.com"
+  }
+}
+
+import {
+  to = infra_identity_provider.okta
+  id = "2c1"
+}
+
+variable "okta_client_secret" {
+  description = "The OIDC client secret of the okta identity provider."
+  type        = string
+  sensitive   = true
+}
+
+resource "infra_identity_provider" "azure" {
+  name          = "azure"
+  client_id     = "azure-client"
+  client_secret = var.azure_client_secret
+  azure {
+    tenant_id = "tenant"
+  }
+}
+
+import {
+  to = infra_identity_provider.azure
+  id = "2c2"
+}
+
+variable "azure_client_secret" {
+  description = "The OIDC client secret of the azure identity provider."
+  type        = string
+  sensitive   = true
+}
+
+resource "infra_identity_provider" "google" {
+  name          = "google"
+  client_id     = "google-client"
+  client_secret = var.google_client_secret
+  google {
+  }
+}
+
+import {
+  to = infra_identity_provider.google
+  id = "2c3"
+}
+
+variable "google_client_secret" {
+  description = "The OIDC client secret of the google identity provider."
+  type        = string
+  sensitive   = true
+}
+
+resource "infra_identity_provider" "oidc" {
+  name          = "oidc"
+  client_id     = "oidc-client"
+  client_secret = var.oidc_client_secret
+  issuer        = "https://idp.example.com"
+}
+
+import {
+  to = infra_identity_provider.oidc
+  id = "2c4"
+}
+
+variable "oidc_client_secret" {
+  description = "The OIDC client secret of the oidc identity provider."
+  type        = string
+  sensitive   = true
+}
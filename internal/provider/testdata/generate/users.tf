@@ -0,0 +1,20 @@
+# Generated by terraform-provider-infra generate. Review this configuration and run
+# "terraform plan" to import the existing objects before making any changes.
+
+resource "infra_user" "alice_example_com" {
+  name = "alice@example.com"
+}
+
+import {
+  to = infra_user.alice_example_com
+  id = "ig"
+}
+
+resource "infra_user" "bob_example_com" {
+  name = "bob@example.com"
+}
+
+import {
+  to = infra_user.bob_example_com
+  id = "ih"
+}
@@ -1,12 +1,14 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net/mail"
 	"regexp"
 	"time"
 
 	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -61,6 +63,17 @@ func validateStringIsDuration() schema.SchemaValidateDiagFunc {
 	}
 }
 
+func validateStringIsAuthorizedKey() schema.SchemaValidateDiagFunc {
+	return func(v any, p cty.Path) diag.Diagnostics {
+		if _, err := ParseAuthorizedKey(v.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		var diags diag.Diagnostics
+		return diags
+	}
+}
+
 func stringMinLength(min int) schema.SchemaValidateDiagFunc {
 	return validation.ToDiagFunc(
 		func(v any, k string) (warnings []string, errors []error) {
@@ -94,3 +107,41 @@ func validateStringIsPEMEncodedFile() schema.SchemaValidateDiagFunc {
 		return diags
 	}
 }
+
+// stringValidator adapts a validation function written for the SDK so it can be used in
+// terraform-plugin-framework schemas.
+type stringValidator struct {
+	description string
+	validate    schema.SchemaValidateDiagFunc
+}
+
+func (v stringValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+func (v stringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.description
+}
+
+func (v stringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, d := range v.validate(req.ConfigValue.ValueString(), cty.Path{}) {
+		if d.Severity == diag.Warning {
+			resp.Diagnostics.AddAttributeWarning(req.Path, d.Summary, d.Detail)
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeError(req.Path, d.Summary, d.Detail)
+	}
+}
+
+func stringIsID() validator.String {
+	return stringValidator{description: "value must be an ID", validate: validateStringIsID()}
+}
+
+func stringIsEmail() validator.String {
+	return stringValidator{description: "value must be an email address", validate: validateStringIsEmail()}
+}
@@ -126,3 +126,28 @@ func TestStringMinLength(t *testing.T) {
 		})
 	}
 }
+
+func TestStringIsAuthorizedKey(t *testing.T) {
+	cases := map[string]diag.Diagnostics{
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEHq8q6XONzwuEnzItsEeF+yilBPmQ6jlQ3lGWzvM0rf":                     nil,
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEHq8q6XONzwuEnzItsEeF+yilBPmQ6jlQ3lGWzvM0rf alice@example.com":   nil,
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEHq8q6XONzwuEnzItsEeF+yilBPmQ6jlQ3lGWzvM0rf alice@example.com\n": nil,
+		"": diag.Diagnostics{
+			{Severity: diag.Error, Summary: "public key must be in authorized_keys format"},
+		},
+		"AAAAC3NzaC1lZDI1NTE5": diag.Diagnostics{
+			{Severity: diag.Error, Summary: "public key must be in authorized_keys format"},
+		},
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEHq8q6XONzwuEnzItsEeF+yilBPmQ6jlQ3lGWzvM0rf\nssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINYKGtApU56jMC4pEFi0kKlL+5QdszvOMg91gFeA0IHM": diag.Diagnostics{
+			{Severity: diag.Error, Summary: "public key must be only a single key"},
+		},
+	}
+
+	fn := validateStringIsAuthorizedKey()
+	for input, expected := range cases {
+		t.Run(input, func(t *testing.T) {
+			actual := fn(input, cty.Path{})
+			assert.DeepEqual(t, actual, expected)
+		})
+	}
+}
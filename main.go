@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"log"
+	"os"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 
 	"github.com/infrahq/terraform-provider-infra/internal/provider"
 )
@@ -13,13 +17,46 @@ import (
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		generate(os.Args[2:])
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	plugin.Serve(&plugin.ServeOpts{
-		Debug:        debug,
-		ProviderFunc: provider.New,
-	})
+	server, err := provider.NewServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var opts []tf5server.ServeOpt
+	if debug {
+		opts = append(opts, tf5server.WithManagedDebug())
+	}
+
+	if err := tf5server.Serve("registry.terraform.io/infrahq/infra", server, opts...); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate writes Terraform configuration for an existing Infra organization. The
+// provider is configured from the same environment variables as in Terraform.
+func generate(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s generate [-dir <directory>]\n\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "Write Terraform configuration and import blocks for the users, groups, grants and identity providers of an Infra organization. Set INFRA_HOST and INFRA_ACCESS_KEY to configure the Infra server.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+
+	dir := flags.String("dir", ".", "directory to write the generated configuration to")
+	_ = flags.Parse(args)
+
+	if err := provider.Generate(context.Background(), *dir, os.Stderr); err != nil {
+		log.Fatal(err)
+	}
 }